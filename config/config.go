@@ -3,7 +3,10 @@ package config
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -15,6 +18,113 @@ type Config struct {
 	DBName      string
 	JWTSecret   []byte
 	AESKey      []byte
+	Mail        MailConfig
+	Storage     StorageConfig
+	Uploads     UploadConfig
+	AssetGC     AssetGCConfig
+	Screening   ScreeningConfig
+	CheckIns    CheckInConfig
+	Popular     PopularTimesConfig
+	WiFi        WiFiConfig
+	Geo         GeoConfig
+}
+
+// GeoConfig controls offline reverse geocoding of hub coordinates
+type GeoConfig struct {
+	BoundariesFile string // GeoJSON boundaries to use instead of the bundled set
+}
+
+// PopularTimesConfig controls the job that builds busyness histograms
+type PopularTimesConfig struct {
+	Interval        time.Duration // 0 disables the job
+	Lookback        time.Duration // check-ins older than this are ignored
+	DefaultTimeZone string        // for hubs without a time zone
+	QuietThreshold  int           // relative busyness (0–100) at or below which a hub is quiet
+}
+
+// WiFiConfig controls the job that keeps hub wifi medians current
+type WiFiConfig struct {
+	RefreshInterval time.Duration // 0 disables the job
+}
+
+// CheckInConfig controls hub check-ins and live crowd levels
+type CheckInConfig struct {
+	Timeout           time.Duration // check-ins end on their own after this
+	MaxDistanceMeters float64       // farther than this from the hub is rejected
+	RequireLocation   bool          // refuse check-ins without coordinates
+	ReportWindow      time.Duration // crowd/noise/wifi reports older than this are ignored
+	ReportHalfLife    time.Duration // a report's weight halves every half-life
+}
+
+// ScreeningConfig tunes the automatic checks run on review text
+type ScreeningConfig struct {
+	WordListFile    string        // extra blocked words, one per line
+	BlockedWords    []string      // extra blocked words, comma separated in env
+	MaxLinks        int           // links allowed before a review is held
+	DuplicateMinLen int           // shorter comments are not checked for copies
+	VelocityWindow  time.Duration // window for the per-user rate check
+	VelocityMax     int           // reviews a user may post per window
+}
+
+// AssetGCConfig controls the background job that deletes unreferenced uploads
+type AssetGCConfig struct {
+	Interval time.Duration // 0 disables the job
+	Grace    time.Duration // assets younger than this are never touched
+	DryRun   bool          // report only, delete nothing
+}
+
+// UploadConfig bounds what clients may upload
+type UploadConfig struct {
+	MaxRequestBytes  int64 // whole request body
+	MaxFileBytes     int64 // each file
+	MaxHubImages     int   // images attached to one hub
+	MaxPendingPhotos int   // photos one user may have awaiting approval on a hub
+	MaxEventImages   int   // images attached to one event
+	Concurrency      int   // parallel uploads per request
+}
+
+// StorageConfig selects and configures where uploaded files are stored.
+type StorageConfig struct {
+	Provider string // cloudinary (default), local or s3
+
+	// Cloudinary
+	CloudinaryCloudName string
+	CloudinaryAPIKey    string
+	CloudinaryAPISecret string
+
+	// Local disk, served by the API under LocalURLPrefix
+	LocalDir       string
+	LocalURLPrefix string
+	PublicBaseURL  string // e.g. https://api.example.com, prepended to local URLs
+
+	// S3-compatible (AWS S3, MinIO)
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+	S3PublicURL string // optional CDN/base URL for objects, defaults to endpoint/bucket
+}
+
+// MailConfig selects and configures the outgoing email transport.
+type MailConfig struct {
+	Transport string // zeptomail (default), smtp or dev
+	From      string
+	FromName  string
+
+	// ZeptoMail HTTP API
+	ZeptoAPIURL string
+	ZeptoAPIKey string
+
+	// SMTP with STARTTLS
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	// Dev sink: .eml files are written here and kept in memory for /dev/mailbox
+	DevDir string
 }
 
 func LoadConfig() (*Config, error) {
@@ -45,7 +155,70 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
-	cfg := &Config{MongoClient: client, DBName: dbName, JWTSecret: []byte(jwt), AESKey: []byte(aes)}
+	mail, err := loadMailConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	storage, err := loadStorageConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	uploads := UploadConfig{
+		MaxRequestBytes:  int64(envInt("UPLOAD_MAX_REQUEST_MB", 25)) << 20,
+		MaxFileBytes:     int64(envInt("UPLOAD_MAX_FILE_MB", 8)) << 20,
+		MaxHubImages:     envInt("UPLOAD_MAX_HUB_IMAGES", 10),
+		MaxPendingPhotos: envInt("UPLOAD_MAX_PENDING_PHOTOS", 5),
+		MaxEventImages:   envInt("UPLOAD_MAX_EVENT_IMAGES", 10),
+		Concurrency:      envInt("UPLOAD_CONCURRENCY", 4),
+	}
+
+	assetGC := AssetGCConfig{
+		Interval: envDuration("ASSET_GC_INTERVAL", 6*time.Hour),
+		Grace:    envDuration("ASSET_GC_GRACE", 24*time.Hour),
+		DryRun:   os.Getenv("ASSET_GC_DRY_RUN") == "true",
+	}
+
+	screening := ScreeningConfig{
+		WordListFile:    os.Getenv("SCREEN_WORDLIST_FILE"),
+		BlockedWords:    envList("SCREEN_BLOCKED_WORDS"),
+		MaxLinks:        envInt("SCREEN_MAX_LINKS", 0),
+		DuplicateMinLen: envInt("SCREEN_DUPLICATE_MIN_LEN", 30),
+		VelocityWindow:  envDuration("SCREEN_VELOCITY_WINDOW", time.Hour),
+		VelocityMax:     envInt("SCREEN_VELOCITY_MAX", 5),
+	}
+
+	checkIns := CheckInConfig{
+		Timeout:           envDuration("CHECKIN_TIMEOUT", 3*time.Hour),
+		MaxDistanceMeters: float64(envInt("CHECKIN_MAX_DISTANCE_M", 300)),
+		RequireLocation:   os.Getenv("CHECKIN_REQUIRE_LOCATION") == "true",
+		ReportWindow:      envDuration("CHECKIN_REPORT_WINDOW", 2*time.Hour),
+		ReportHalfLife:    envDuration("CHECKIN_REPORT_HALF_LIFE", 30*time.Minute),
+	}
+
+	popular := PopularTimesConfig{
+		Interval:        envDuration("POPULAR_TIMES_INTERVAL", time.Hour),
+		Lookback:        envDuration("POPULAR_TIMES_LOOKBACK", 8*7*24*time.Hour),
+		DefaultTimeZone: os.Getenv("DEFAULT_TIMEZONE"),
+		QuietThreshold:  envInt("POPULAR_TIMES_QUIET", 40),
+	}
+	if popular.DefaultTimeZone == "" {
+		popular.DefaultTimeZone = "Africa/Nairobi"
+	}
+	if _, err := time.LoadLocation(popular.DefaultTimeZone); err != nil {
+		return nil, fmt.Errorf("DEFAULT_TIMEZONE: %w", err)
+	}
+
+	wifi := WiFiConfig{
+		RefreshInterval: envDuration("WIFI_REFRESH_INTERVAL", time.Hour),
+	}
+
+	geo := GeoConfig{
+		BoundariesFile: os.Getenv("GEO_BOUNDARIES_FILE"),
+	}
+
+	cfg := &Config{MongoClient: client, DBName: dbName, JWTSecret: []byte(jwt), AESKey: []byte(aes), Mail: mail, Storage: storage, Uploads: uploads, AssetGC: assetGC, Screening: screening, CheckIns: checkIns, Popular: popular, WiFi: wifi, Geo: geo}
 
 	// ensure indexes
 	// if err := ensureIndexes(cfg); err != nil {
@@ -55,6 +228,124 @@ func LoadConfig() (*Config, error) {
 	return cfg, nil
 }
 
+// envInt reads a positive integer from the environment, falling back to def
+func envInt(name string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || v <= 0 {
+		return def
+	}
+	return v
+}
+
+// envDuration reads a duration such as "6h" from the environment, falling
+// back to def. "0" is kept so features can be switched off.
+func envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return def
+	}
+	return d
+}
+
+func envList(name string) []string {
+	var out []string
+	for _, v := range strings.Split(os.Getenv(name), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func loadMailConfig() (MailConfig, error) {
+	mail := MailConfig{
+		Transport:    strings.ToLower(os.Getenv("MAIL_TRANSPORT")),
+		From:         os.Getenv("EMAIL_FROM"),
+		FromName:     os.Getenv("EMAIL_FROM_NAME"),
+		ZeptoAPIURL:  os.Getenv("ZEPTO_API_URL"),
+		ZeptoAPIKey:  os.Getenv("ZEPTO_API_KEY"),
+		SMTPHost:     os.Getenv("SMTP_HOST"),
+		SMTPPort:     os.Getenv("SMTP_PORT"),
+		SMTPUsername: os.Getenv("SMTP_USERNAME"),
+		SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+		DevDir:       os.Getenv("MAIL_DEV_DIR"),
+	}
+	if mail.Transport == "" {
+		mail.Transport = "zeptomail"
+	}
+	if mail.SMTPPort == "" {
+		mail.SMTPPort = "587"
+	}
+	if mail.FromName == "" {
+		mail.FromName = "Laptopers"
+	}
+
+	switch mail.Transport {
+	case "zeptomail":
+		if mail.ZeptoAPIURL == "" || mail.ZeptoAPIKey == "" || mail.From == "" {
+			return mail, errors.New("ZEPTO_API_URL, ZEPTO_API_KEY and EMAIL_FROM required for zeptomail transport")
+		}
+	case "smtp":
+		if mail.SMTPHost == "" || mail.From == "" {
+			return mail, errors.New("SMTP_HOST and EMAIL_FROM required for smtp transport")
+		}
+	case "dev":
+		if mail.From == "" {
+			mail.From = "noreply@localhost"
+		}
+	default:
+		return mail, fmt.Errorf("unknown MAIL_TRANSPORT %q (use zeptomail, smtp or dev)", mail.Transport)
+	}
+	return mail, nil
+}
+
+func loadStorageConfig() (StorageConfig, error) {
+	st := StorageConfig{
+		Provider:            strings.ToLower(os.Getenv("STORAGE_PROVIDER")),
+		CloudinaryCloudName: os.Getenv("CLOUDINARY_CLOUD_NAME"),
+		CloudinaryAPIKey:    os.Getenv("CLOUDINARY_API_KEY"),
+		CloudinaryAPISecret: os.Getenv("CLOUDINARY_API_SECRET"),
+		LocalDir:            os.Getenv("STORAGE_LOCAL_DIR"),
+		LocalURLPrefix:      os.Getenv("STORAGE_LOCAL_URL_PREFIX"),
+		PublicBaseURL:       strings.TrimRight(os.Getenv("PUBLIC_BASE_URL"), "/"),
+		S3Endpoint:          os.Getenv("S3_ENDPOINT"),
+		S3Region:            os.Getenv("S3_REGION"),
+		S3Bucket:            os.Getenv("S3_BUCKET"),
+		S3AccessKey:         os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:         os.Getenv("S3_SECRET_KEY"),
+		S3UseSSL:            os.Getenv("S3_USE_SSL") != "false",
+		S3PublicURL:         strings.TrimRight(os.Getenv("S3_PUBLIC_URL"), "/"),
+	}
+	if st.Provider == "" {
+		st.Provider = "cloudinary"
+	}
+	if st.LocalDir == "" {
+		st.LocalDir = "uploads"
+	}
+	if st.LocalURLPrefix == "" {
+		st.LocalURLPrefix = "/uploads"
+	}
+
+	switch st.Provider {
+	case "cloudinary":
+		if st.CloudinaryCloudName == "" || st.CloudinaryAPIKey == "" || st.CloudinaryAPISecret == "" {
+			return st, errors.New("CLOUDINARY_CLOUD_NAME, CLOUDINARY_API_KEY and CLOUDINARY_API_SECRET required for cloudinary storage")
+		}
+	case "local":
+	case "s3":
+		if st.S3Endpoint == "" || st.S3Bucket == "" || st.S3AccessKey == "" || st.S3SecretKey == "" {
+			return st, errors.New("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY required for s3 storage")
+		}
+	default:
+		return st, fmt.Errorf("unknown STORAGE_PROVIDER %q (use cloudinary, local or s3)", st.Provider)
+	}
+	return st, nil
+}
+
 // func ensureIndexes(cfg *Config) error {
 // 	// db := cfg.MongoClient.Database(cfg.DBName)
 // 	// users unique email
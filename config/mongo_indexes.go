@@ -1,9 +1,453 @@
 package config
 
 import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// EnsureAssetIndexes creates indexes for the assets collection
+func EnsureAssetIndexes(client *mongo.Client, dbName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Database(dbName).Collection("assets")
+
+	createdIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetBackground(true),
+	}
+
+	_, err := col.Indexes().CreateMany(ctx, []mongo.IndexModel{createdIdx})
+	if err != nil {
+		log.Printf("⚠️ Could not create asset indexes: %v", err)
+	} else {
+		log.Println("✅ Asset indexes ensured")
+	}
+}
+
+// EnsureHubPhotoIndexes creates indexes for hub galleries. An asset
+// belongs to exactly one photo record.
+func EnsureHubPhotoIndexes(client *mongo.Client, dbName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Database(dbName).Collection("hub_photos")
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "asset._id", Value: 1}}, Options: options.Index().SetUnique(true).SetName("one_photo_per_asset")},
+		{Keys: bson.D{{Key: "hub_id", Value: 1}, {Key: "status", Value: 1}, {Key: "position", Value: 1}}, Options: options.Index().SetBackground(true)},
+	}
+	if _, err := col.Indexes().CreateMany(ctx, indexes); err != nil {
+		log.Printf("⚠️ Could not create hub photo indexes: %v", err)
+		return
+	}
+	log.Println("✅ Hub photo indexes ensured")
+}
+
+// EnsureHubHistoryIndexes creates indexes for edit suggestions and revisions.
+// Revision numbers are unique per hub.
+func EnsureHubHistoryIndexes(client *mongo.Client, dbName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	editIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "hub_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetBackground(true),
+	}
+	revisionIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "hub_id", Value: 1}, {Key: "number", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	db := client.Database(dbName)
+	if _, err := db.Collection("hub_edits").Indexes().CreateMany(ctx, []mongo.IndexModel{editIdx}); err != nil {
+		log.Printf("⚠️ Could not create hub edit indexes: %v", err)
+		return
+	}
+	if _, err := db.Collection("hub_revisions").Indexes().CreateMany(ctx, []mongo.IndexModel{revisionIdx}); err != nil {
+		log.Printf("⚠️ Could not create hub revision indexes: %v", err)
+		return
+	}
+	log.Println("✅ Hub history indexes ensured")
+}
+
+// EnsureReportIndexes creates indexes for the moderation queue
+func EnsureReportIndexes(client *mongo.Client, dbName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Database(dbName).Collection("reports")
+
+	queueIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}},
+		Options: options.Index().SetBackground(true),
+	}
+	targetIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "status", Value: 1}},
+		Options: options.Index().SetBackground(true),
+	}
+
+	_, err := col.Indexes().CreateMany(ctx, []mongo.IndexModel{queueIdx, targetIdx})
+	if err != nil {
+		log.Printf("⚠️ Could not create report indexes: %v", err)
+	} else {
+		log.Println("✅ Report indexes ensured")
+	}
+}
+
+// EnsureReviewIndexes creates indexes used by review screening
+func EnsureReviewIndexes(client *mongo.Client, dbName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Database(dbName).Collection("reviews")
+
+	hashIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "text_hash", Value: 1}},
+		Options: options.Index().SetBackground(true).SetSparse(true),
+	}
+	velocityIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetBackground(true),
+	}
+	hubIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "hub_id", Value: 1}},
+		Options: options.Index().SetBackground(true),
+	}
+
+	_, err := col.Indexes().CreateMany(ctx, []mongo.IndexModel{hashIdx, velocityIdx, hubIdx})
+	if err != nil {
+		log.Printf("⚠️ Could not create review indexes: %v", err)
+	} else {
+		log.Println("✅ Review indexes ensured")
+	}
+}
+
+// EnsureCheckInIndexes creates indexes for the checkins collection. The
+// partial unique index allows only one active check-in per user.
+func EnsureCheckInIndexes(client *mongo.Client, dbName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Database(dbName).Collection("checkins")
+
+	activeUserIdx := mongo.IndexModel{
+		Keys: bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"active": true}).
+			SetName("one_active_checkin_per_user"),
+	}
+	activeHubIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "hub_id", Value: 1}, {Key: "active", Value: 1}, {Key: "expires_at", Value: 1}},
+		Options: options.Index().SetBackground(true),
+	}
+	reportsIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "hub_id", Value: 1}, {Key: "reported_at", Value: -1}},
+		Options: options.Index().SetBackground(true).SetSparse(true),
+	}
+	historyIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "hub_id", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetBackground(true),
+	}
+	expiryIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "active", Value: 1}, {Key: "expires_at", Value: 1}},
+		Options: options.Index().SetBackground(true),
+	}
+	userHistoryIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetBackground(true),
+	}
+
+	_, err := col.Indexes().CreateMany(ctx, []mongo.IndexModel{activeUserIdx, activeHubIdx, reportsIdx, historyIdx, expiryIdx, userHistoryIdx})
+	if err != nil {
+		log.Printf("⚠️ Could not create check-in indexes: %v", err)
+	} else {
+		log.Println("✅ Check-in indexes ensured")
+	}
+}
+
+// EnsurePopularTimesIndexes creates indexes for the popular_times collection
+func EnsurePopularTimesIndexes(client *mongo.Client, dbName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Database(dbName).Collection("popular_times")
+
+	hubIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "hub_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+
+	_, err := col.Indexes().CreateMany(ctx, []mongo.IndexModel{hubIdx})
+	if err != nil {
+		log.Printf("⚠️ Could not create popular times indexes: %v", err)
+	} else {
+		log.Println("✅ Popular times indexes ensured")
+	}
+}
+
+// EnsureWiFiIndexes creates indexes for speed tests and the hub fields
+// ListHubs filters and sorts them by
+func EnsureWiFiIndexes(client *mongo.Client, dbName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	db := client.Database(dbName)
+
+	recentIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "hub_id", Value: 1}, {Key: "measured_at", Value: -1}},
+		Options: options.Index().SetBackground(true),
+	}
+	userIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "hub_id", Value: 1}, {Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetBackground(true),
+	}
+	if _, err := db.Collection("wifi_tests").Indexes().CreateMany(ctx, []mongo.IndexModel{recentIdx, userIdx}); err != nil {
+		log.Printf("⚠️ Could not create wifi test indexes: %v", err)
+		return
+	}
+
+	hubIdx := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "wifi.download_mbps", Value: -1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "wifi.upload_mbps", Value: -1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "wifi.latency_ms", Value: 1}}, Options: options.Index().SetSparse(true)},
+	}
+	if _, err := db.Collection("hubs").Indexes().CreateMany(ctx, hubIdx); err != nil {
+		log.Printf("⚠️ Could not create hub wifi indexes: %v", err)
+		return
+	}
+	log.Println("✅ WiFi indexes ensured")
+}
+
+// EnsureFavoriteIndexes makes (user_id, hub_id) unique in favorites,
+// first removing duplicates left by the old toggle endpoint
+func EnsureFavoriteIndexes(client *mongo.Client, dbName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	col := client.Database(dbName).Collection("favorites")
+
+	cursor, err := col.Aggregate(ctx, bson.A{
+		bson.M{"$sort": bson.M{"created_at": 1}},
+		bson.M{"$group": bson.M{
+			"_id":   bson.M{"user_id": "$user_id", "hub_id": "$hub_id"},
+			"ids":   bson.M{"$push": "$_id"},
+			"count": bson.M{"$sum": 1},
+		}},
+		bson.M{"$match": bson.M{"count": bson.M{"$gt": 1}}},
+	})
+	if err != nil {
+		log.Printf("⚠️ Could not check duplicate favorites: %v", err)
+		return
+	}
+	var dups []struct {
+		IDs []interface{} `bson:"ids"`
+	}
+	if err := cursor.All(ctx, &dups); err != nil {
+		log.Printf("⚠️ Could not check duplicate favorites: %v", err)
+		return
+	}
+	for _, d := range dups {
+		// keep the oldest
+		if _, err := col.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": d.IDs[1:]}}); err != nil {
+			log.Printf("⚠️ Could not remove duplicate favorites: %v", err)
+			return
+		}
+	}
+	if len(dups) > 0 {
+		log.Printf("✅ Removed duplicate favorites for %d user/hub pairs", len(dups))
+	}
+
+	uniqueIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "hub_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	listIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetBackground(true),
+	}
+	hubIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "hub_id", Value: 1}},
+		Options: options.Index().SetBackground(true),
+	}
+
+	if _, err := col.Indexes().CreateMany(ctx, []mongo.IndexModel{uniqueIdx, listIdx, hubIdx}); err != nil {
+		log.Printf("⚠️ Could not create favorite indexes: %v", err)
+		return
+	}
+	if _, err := client.Database(dbName).Collection("hubs").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "favorites_count", Value: -1}},
+	}); err != nil {
+		log.Printf("⚠️ Could not create hub favorites_count index: %v", err)
+		return
+	}
+	log.Println("✅ Favorite indexes ensured")
+}
+
+// EnsureCollectionIndexes creates indexes for hub collections
+func EnsureCollectionIndexes(client *mongo.Client, dbName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Database(dbName).Collection("collections")
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "slug", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "updated_at", Value: -1}}},
+		{Keys: bson.D{{Key: "collaborators", Value: 1}}},
+		{Keys: bson.D{{Key: "invited", Value: 1}}},
+		{Keys: bson.D{{Key: "visibility", Value: 1}, {Key: "updated_at", Value: -1}}},
+		{Keys: bson.D{{Key: "items.hub_id", Value: 1}}},
+	}
+	if _, err := col.Indexes().CreateMany(ctx, indexes); err != nil {
+		log.Printf("⚠️ Could not create collection indexes: %v", err)
+		return
+	}
+	log.Println("✅ Collection indexes ensured")
+}
+
+// EnsureHubSearchIndexes creates the full-text index behind ?q= and the
+// indexes for the facet filters. A collection can only have one text index.
+func EnsureHubSearchIndexes(client *mongo.Client, dbName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	col := client.Database(dbName).Collection("hubs")
+	textIdx := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "title", Value: "text"},
+			{Key: "location", Value: "text"},
+			{Key: "neighbourhood", Value: "text"},
+			{Key: "categories", Value: "text"},
+			{Key: "tags", Value: "text"},
+			{Key: "amenities", Value: "text"},
+			{Key: "description", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("hub_text").
+			SetWeights(bson.M{
+				"title": 10, "location": 5, "neighbourhood": 5,
+				"categories": 4, "tags": 3, "amenities": 3, "description": 1,
+			}),
+	}
+	amenitiesIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "amenities", Value: 1}},
+		Options: options.Index().SetBackground(true),
+	}
+	neighbourhoodIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "neighbourhood", Value: 1}},
+		Options: options.Index().SetBackground(true).SetSparse(true),
+	}
+	priceIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "price_band", Value: 1}},
+		Options: options.Index().SetBackground(true).SetSparse(true),
+	}
+	categoriesIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "categories", Value: 1}},
+		Options: options.Index().SetBackground(true),
+	}
+	tagsIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "tags", Value: 1}},
+		Options: options.Index().SetBackground(true),
+	}
+
+	if _, err := col.Indexes().CreateMany(ctx, []mongo.IndexModel{textIdx, amenitiesIdx, neighbourhoodIdx, priceIdx, categoriesIdx, tagsIdx}); err != nil {
+		log.Printf("⚠️ Could not create hub search indexes: %v", err)
+		return
+	}
+	log.Println("✅ Hub search indexes ensured")
+}
+
+// EnsureTaxonomyIndexes creates indexes for the curated taxonomy and tag
+// synonyms
+func EnsureTaxonomyIndexes(client *mongo.Client, dbName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	db := client.Database(dbName)
+	termIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "kind", Value: 1}, {Key: "slug", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	if _, err := db.Collection("taxonomy").Indexes().CreateOne(ctx, termIdx); err != nil {
+		log.Printf("⚠️ Could not create taxonomy indexes: %v", err)
+		return
+	}
+
+	synonymIdx := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "alias", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "tag", Value: 1}}, Options: options.Index().SetBackground(true)},
+	}
+	if _, err := db.Collection("tag_synonyms").Indexes().CreateMany(ctx, synonymIdx); err != nil {
+		log.Printf("⚠️ Could not create tag synonym indexes: %v", err)
+		return
+	}
+	log.Println("✅ Taxonomy indexes ensured")
+}
+
+// EnsureHubGeoIndexes creates the index behind bounding-box queries on
+// hub coordinates (map clusters, duplicate detection)
+func EnsureHubGeoIndexes(client *mongo.Client, dbName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Database(dbName).Collection("hubs")
+	boxIdx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "coordinates.lat", Value: 1}, {Key: "coordinates.lng", Value: 1}},
+		Options: options.Index().SetBackground(true),
+	}
+	if _, err := col.Indexes().CreateOne(ctx, boxIdx); err != nil {
+		log.Printf("⚠️ Could not create hub geo indexes: %v", err)
+		return
+	}
+	log.Println("✅ Hub geo indexes ensured")
+}
+
+// EnsureHubImportIndexes creates the index behind listing recent imports
+func EnsureHubImportIndexes(client *mongo.Client, dbName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Database(dbName).Collection("hub_imports")
+	idx := mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: -1}},
+		Options: options.Index().SetBackground(true),
+	}
+	if _, err := col.Indexes().CreateOne(ctx, idx); err != nil {
+		log.Printf("⚠️ Could not create hub import indexes: %v", err)
+		return
+	}
+	log.Println("✅ Hub import indexes ensured")
+}
+
+// EnsureHubClaimIndexes creates indexes for the claim review queue and
+// allows one pending claim per user and hub
+func EnsureHubClaimIndexes(client *mongo.Client, dbName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	col := client.Database(dbName).Collection("hub_claims")
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "hub_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("one_pending_claim").
+				SetPartialFilterExpression(bson.M{"status": "pending"}),
+		},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: 1}}, Options: options.Index().SetBackground(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetBackground(true)},
+		{Keys: bson.D{{Key: "hub_id", Value: 1}, {Key: "created_at", Value: -1}}, Options: options.Index().SetBackground(true)},
+	}
+	if _, err := col.Indexes().CreateMany(ctx, indexes); err != nil {
+		log.Printf("⚠️ Could not create hub claim indexes: %v", err)
+		return
+	}
+	log.Println("✅ Hub claim indexes ensured")
+}
+
 // EnsureCategoryIndexes creates indexes for the categories collection
 // func EnsureCategoryIndexes(client *mongo.Client, dbName string) {
 // 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -32,4 +476,19 @@ import (
 // EnsureAllIndexes creates indexes for all collections
 func EnsureAllIndexes(client *mongo.Client, dbName string) {
 	// EnsureCategoryIndexes(client, dbName)
+	EnsureAssetIndexes(client, dbName)
+	EnsureHubPhotoIndexes(client, dbName)
+	EnsureHubHistoryIndexes(client, dbName)
+	EnsureReportIndexes(client, dbName)
+	EnsureReviewIndexes(client, dbName)
+	EnsureCheckInIndexes(client, dbName)
+	EnsurePopularTimesIndexes(client, dbName)
+	EnsureWiFiIndexes(client, dbName)
+	EnsureFavoriteIndexes(client, dbName)
+	EnsureCollectionIndexes(client, dbName)
+	EnsureHubSearchIndexes(client, dbName)
+	EnsureTaxonomyIndexes(client, dbName)
+	EnsureHubGeoIndexes(client, dbName)
+	EnsureHubImportIndexes(client, dbName)
+	EnsureHubClaimIndexes(client, dbName)
 }
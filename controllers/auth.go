@@ -26,8 +26,8 @@ func Register(cfg *config.Config) gin.HandlerFunc {
 		var input struct {
 			Name  string `json:"name" binding:"required"`
 			Email string `json:"email" binding:"required,email"`
-			Role  string `json:"role" binding:"required"`
 			Phone  string `json:"phone" binding:"required"`
+			Locale string `json:"locale"`
 		}
 
 		if err := c.ShouldBindJSON(&input); err != nil {
@@ -58,7 +58,8 @@ func Register(cfg *config.Config) gin.HandlerFunc {
 			Name:      input.Name,
 			Email:     input.Email,
 			Phone:     input.Phone,
-			Role:     input.Role,
+			Role:     "user", // roles are granted by an admin, never chosen at sign-up
+			Locale:    utils.NormalizeLocale(input.Locale + "," + c.GetHeader("Accept-Language")),
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		}
@@ -75,8 +76,7 @@ func Register(cfg *config.Config) gin.HandlerFunc {
 		users.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$set": bson.M{"otp": otp, "otp_expiry": expiry}})
 
 		// Send OTP
-		body := utils.BuildOtpEmail(user.Email, otp)
-		go utils.SendEmail(user.Email, "Verify your account", body)
+		go utils.SendOTPEmail(user, otp, "verify", 10*time.Minute)
 
 
 
@@ -143,8 +143,7 @@ func Login(cfg *config.Config) gin.HandlerFunc {
 		}
 
 		// Send OTP (always to associated email)
-		body := utils.BuildOtpEmail(user.Email, otp)
-		go utils.SendEmail(user.Email, "Your Login OTP", body)
+		go utils.SendOTPEmail(user, otp, "login", 10*time.Minute)
 
 		c.JSON(http.StatusOK, gin.H{
 			"status":  200,
@@ -187,8 +186,22 @@ func VerifyOTP(cfg *config.Config) gin.HandlerFunc {
 		// Clear OTP
 		users.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$unset": bson.M{"otp": "", "otp_expiry": ""}})
 
+		// First successful verification: mark verified and say welcome.
+		// Accounts that predate verified_at are marked silently.
+		if user.VerifiedAt == nil {
+			now := time.Now()
+			res, err := users.UpdateOne(ctx,
+				bson.M{"_id": user.ID, "verified_at": bson.M{"$exists": false}},
+				bson.M{"$set": bson.M{"verified_at": now}},
+			)
+			if err == nil && res.ModifiedCount == 1 && now.Sub(user.CreatedAt) < 24*time.Hour {
+				user.VerifiedAt = &now
+				go utils.SendWelcomeEmail(user)
+			}
+		}
+
 		// Create tokens
-		accessToken, refreshToken, _ := createTokensForUser(user.ID, cfg)
+		accessToken, refreshToken, _ := createTokensForUser(user.ID, user.Role, cfg)
 		users.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$set": bson.M{"refresh_token": refreshToken}})
 
 		c.JSON(http.StatusOK, gin.H{
@@ -201,6 +214,7 @@ func VerifyOTP(cfg *config.Config) gin.HandlerFunc {
 				"email": user.Email,
 				"phone": user.Phone,
 				"role": user.Role,
+				"locale": user.Locale,
 			},
 		})
 	}
@@ -251,7 +265,7 @@ func RefreshToken(cfg *config.Config) gin.HandlerFunc {
 		}
 
 		// Create new tokens
-		accessToken, refreshToken, _ := createTokensForUser(user.ID, cfg)
+		accessToken, refreshToken, _ := createTokensForUser(user.ID, user.Role, cfg)
 
 		// Rotate refresh token
 		users.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$set": bson.M{"refresh_token": refreshToken}})
@@ -266,10 +280,11 @@ func RefreshToken(cfg *config.Config) gin.HandlerFunc {
 // =============================
 // Helpers
 // =============================
-func createTokensForUser(uid primitive.ObjectID, cfg *config.Config) (accessToken string, refreshToken string, err error) {
+func createTokensForUser(uid primitive.ObjectID, role string, cfg *config.Config) (accessToken string, refreshToken string, err error) {
 	// Access Token (short-lived)
 	accessClaims := jwt.MapClaims{
 		"user_id": uid.Hex(),
+		"role":    role,
 		"exp":     time.Now().Add(15 * time.Minute).Unix(),
 		"iat":     time.Now().Unix(),
 	}
@@ -0,0 +1,294 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+// ---------------- CHECK IN ----------------
+// CheckIn marks the caller as at the hub, with optional crowd, noise and
+// Wi-Fi reports. Users have one active check-in: checking in elsewhere ends
+// the previous one, checking in again at the same hub refreshes it.
+func CheckIn(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		var input struct {
+			Lat   *float64 `json:"lat" binding:"omitempty,min=-90,max=90"`
+			Lng   *float64 `json:"lng" binding:"omitempty,min=-180,max=180"`
+			Crowd *int     `json:"crowd" binding:"omitempty,min=1,max=5"`
+			Noise *int     `json:"noise" binding:"omitempty,min=1,max=5"`
+			WiFi  *int     `json:"wifi" binding:"omitempty,min=1,max=5"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if (input.Lat == nil) != (input.Lng == nil) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "lat and lng must be sent together"})
+			return
+		}
+		if input.Lat == nil && cfg.CheckIns.RequireLocation {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "your location is required to check in"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var hub models.Hub
+		err = cfg.MongoClient.Database(cfg.DBName).Collection("hubs").FindOne(ctx, bson.M{
+			"_id":         hubID,
+			"merged_into": bson.M{"$exists": false},
+			"hidden":      bson.M{"$ne": true},
+		}).Decode(&hub)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+
+		now := time.Now()
+		checkIn := models.CheckIn{
+			ID:        primitive.NewObjectID(),
+			HubID:     hubID,
+			UserID:    userID,
+			Active:    true,
+			Crowd:     input.Crowd,
+			Noise:     input.Noise,
+			WiFi:      input.WiFi,
+			CreatedAt: now,
+			ExpiresAt: now.Add(cfg.CheckIns.Timeout),
+		}
+		if input.Crowd != nil || input.Noise != nil || input.WiFi != nil {
+			checkIn.ReportedAt = &now
+		}
+
+		// --- Verify distance when we know both locations ---
+		if input.Lat != nil {
+			checkIn.Coordinates = &models.Coordinates{Lat: *input.Lat, Lng: *input.Lng}
+			if hub.Coordinates.Lat != 0 || hub.Coordinates.Lng != 0 {
+				dist := math.Round(utils.HaversineMeters(*checkIn.Coordinates, hub.Coordinates))
+				if dist > cfg.CheckIns.MaxDistanceMeters {
+					c.JSON(http.StatusUnprocessableEntity, gin.H{
+						"error":           "you are too far from this hub to check in",
+						"distance_meters": dist,
+					})
+					return
+				}
+				checkIn.DistanceMeters = &dist
+				checkIn.Verified = true
+			}
+		}
+
+		col := cfg.MongoClient.Database(cfg.DBName).Collection("checkins")
+
+		// --- One active check-in per user ---
+		var current models.CheckIn
+		err = col.FindOne(ctx, bson.M{"user_id": userID, "active": true}).Decode(&current)
+		switch {
+		case err == nil && current.HubID == hubID && current.ExpiresAt.After(now):
+			set := bson.M{"expires_at": checkIn.ExpiresAt}
+			if checkIn.ReportedAt != nil {
+				set["crowd"], set["noise"], set["wifi"], set["reported_at"] = input.Crowd, input.Noise, input.WiFi, now
+			}
+			if checkIn.Verified {
+				set["coordinates"], set["distance_m"], set["verified"] = checkIn.Coordinates, checkIn.DistanceMeters, true
+			}
+			if _, err := col.UpdateOne(ctx, bson.M{"_id": current.ID}, bson.M{"$set": set}); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not refresh check-in"})
+				return
+			}
+			if err := col.FindOne(ctx, bson.M{"_id": current.ID}).Decode(&checkIn); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load check-in"})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"checkin": checkIn, "live": liveStatus(ctx, cfg, hubID)})
+			return
+		case err == nil:
+			if _, err := endCheckIn(ctx, cfg, current.ID, now); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not end previous check-in"})
+				return
+			}
+		case err != mongo.ErrNoDocuments:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not check in"})
+			return
+		}
+
+		if _, err := col.InsertOne(ctx, checkIn); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				c.JSON(http.StatusConflict, gin.H{"error": "you are already checked in"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not check in"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"checkin": checkIn, "live": liveStatus(ctx, cfg, hubID)})
+	}
+}
+
+// ---------------- CHECK OUT ----------------
+func CheckOut(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var current models.CheckIn
+		err = cfg.MongoClient.Database(cfg.DBName).Collection("checkins").
+			FindOne(ctx, bson.M{"user_id": userID, "hub_id": hubID, "active": true}).
+			Decode(&current)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "you are not checked in here"})
+			return
+		}
+
+		if _, err := endCheckIn(ctx, cfg, current.ID, time.Now()); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not check out"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "checked out",
+			"id":      current.ID.Hex(),
+		})
+	}
+}
+
+// =============================
+// Helpers
+// =============================
+
+// endCheckIn closes an active check-in, never later than its expiry
+func endCheckIn(ctx context.Context, cfg *config.Config, id primitive.ObjectID, at time.Time) (bool, error) {
+	res, err := cfg.MongoClient.Database(cfg.DBName).Collection("checkins").UpdateOne(ctx,
+		bson.M{"_id": id, "active": true},
+		bson.A{bson.M{"$set": bson.M{
+			"active":         false,
+			"checked_out_at": bson.M{"$min": bson.A{at, "$expires_at"}},
+		}}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// liveStatus is HubLiveStatus for responses; failures give an empty status
+func liveStatus(ctx context.Context, cfg *config.Config, hubID primitive.ObjectID) models.LiveStatus {
+	live, err := utils.HubLiveStatus(ctx, cfg, hubID)
+	if err != nil {
+		log.Printf("⚠️ could not load live status of hub %s: %v", hubID.Hex(), err)
+	}
+	return live
+}
+
+// ---------------- POPULAR TIMES ----------------
+// GetPopularTimes returns the hub's weekly busyness histogram and how the
+// current hour usually compares
+func GetPopularTimes(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		db := cfg.MongoClient.Database(cfg.DBName)
+		var hub models.Hub
+		if err := db.Collection("hubs").FindOne(ctx, bson.M{"_id": hubID}).Decode(&hub); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+
+		loc := utils.HubLocation(cfg, hub.TimeZone)
+		var pt models.PopularTimes
+		err = db.Collection("popular_times").FindOne(ctx, bson.M{"hub_id": hubID}).Decode(&pt)
+		if err == mongo.ErrNoDocuments {
+			pt = models.PopularTimes{HubID: hubID, TimeZone: loc.String()}
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load popular times"})
+			return
+		}
+
+		now := utils.PopularTimesNow(pt, loc, time.Now(), cfg.Popular.QuietThreshold)
+		now.ActiveCheckIns = liveStatus(ctx, cfg, hubID).ActiveCheckIns
+
+		c.JSON(http.StatusOK, gin.H{
+			"popular_times": pt,
+			"now":           now,
+			"has_data":      pt.CheckIns > 0,
+		})
+	}
+}
+
+// quietHubs keeps the hubs whose usual busyness for the current local hour
+// is at or below the quiet threshold. Hubs without history are dropped.
+func quietHubs(ctx context.Context, cfg *config.Config, hubs []models.Hub) ([]models.Hub, error) {
+	ids := make([]primitive.ObjectID, 0, len(hubs))
+	for _, h := range hubs {
+		ids = append(ids, h.ID)
+	}
+	cursor, err := cfg.MongoClient.Database(cfg.DBName).Collection("popular_times").
+		Find(ctx, bson.M{"hub_id": bson.M{"$in": ids}, "checkins": bson.M{"$gt": 0}})
+	if err != nil {
+		return nil, err
+	}
+	var all []models.PopularTimes
+	if err := cursor.All(ctx, &all); err != nil {
+		return nil, err
+	}
+	byHub := map[primitive.ObjectID]models.PopularTimes{}
+	for _, pt := range all {
+		byHub[pt.HubID] = pt
+	}
+
+	now := time.Now()
+	quiet := []models.Hub{}
+	for _, h := range hubs {
+		pt, ok := byHub[h.ID]
+		if !ok {
+			continue
+		}
+		if utils.PopularTimesNow(pt, utils.HubLocation(cfg, h.TimeZone), now, cfg.Popular.QuietThreshold).Quiet {
+			quiet = append(quiet, h)
+		}
+	}
+	return quiet, nil
+}
@@ -0,0 +1,609 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+// maxClaimEvidence caps the files attached to one claim
+const maxClaimEvidence = 5
+
+// ---------------- SUBMIT CLAIM ----------------
+// SubmitHubClaim asks for ownership of a hub. It takes form fields and up
+// to maxClaimEvidence images under "evidence" (permit, storefront, ...);
+// at least one file or a registration number is required. A caller can
+// have one pending claim per hub.
+func SubmitHubClaim(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		var input struct {
+			BusinessName       string `form:"business_name" binding:"required,max=200"`
+			Role               string `form:"role" binding:"required,oneof=owner manager"`
+			ContactEmail       string `form:"contact_email" binding:"omitempty,email"`
+			ContactPhone       string `form:"contact_phone" binding:"max=40"`
+			Website            string `form:"website" binding:"omitempty,url"`
+			RegistrationNumber string `form:"registration_number" binding:"max=100"`
+			Note               string `form:"note" binding:"max=1000"`
+		}
+		if err := c.ShouldBind(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		db := cfg.MongoClient.Database(cfg.DBName)
+		var hub models.Hub
+		err = db.Collection("hubs").FindOne(ctx, bson.M{
+			"_id":         hubID,
+			"merged_into": bson.M{"$exists": false},
+			"hidden":      bson.M{"$ne": true},
+		}).Decode(&hub)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+		if hub.Verified && hub.UserID == userID {
+			c.JSON(http.StatusConflict, gin.H{"error": "you already own this hub"})
+			return
+		}
+		pending, err := db.Collection("hub_claims").CountDocuments(ctx, bson.M{
+			"hub_id": hubID, "user_id": userID, "status": models.ClaimStatusPending,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not check claims"})
+			return
+		}
+		if pending > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "you already have a pending claim on this hub"})
+			return
+		}
+
+		uploads, ok := imageUploads(c, "evidence", utils.ImageLimits{
+			MaxFileBytes: cfg.Uploads.MaxFileBytes,
+			MaxFiles:     maxClaimEvidence,
+		})
+		if !ok {
+			return
+		}
+		if len(uploads) == 0 && strings.TrimSpace(input.RegistrationNumber) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "attach evidence or give a business registration number"})
+			return
+		}
+		evidence, results, err := utils.UploadImages(cfg, "claims", uploads, userID)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "evidence upload failed", "uploads": results})
+			return
+		}
+
+		now := time.Now()
+		claim := models.HubClaim{
+			ID:                 primitive.NewObjectID(),
+			HubID:              hubID,
+			UserID:             userID,
+			BusinessName:       strings.TrimSpace(input.BusinessName),
+			Role:               input.Role,
+			ContactEmail:       input.ContactEmail,
+			ContactPhone:       input.ContactPhone,
+			Website:            input.Website,
+			RegistrationNumber: strings.TrimSpace(input.RegistrationNumber),
+			Note:               input.Note,
+			Evidence:           evidence,
+			Status:             models.ClaimStatusPending,
+			History:            []models.ClaimEvent{{Action: models.ClaimActionSubmitted, ActorID: userID, At: now}},
+			CreatedAt:          now,
+			UpdatedAt:          now,
+		}
+
+		writeCtx, writeCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer writeCancel()
+
+		if _, err := db.Collection("hub_claims").InsertOne(writeCtx, claim); err != nil {
+			utils.RollbackUploads(cfg, evidence)
+			if mongo.IsDuplicateKeyError(err) {
+				c.JSON(http.StatusConflict, gin.H{"error": "you already have a pending claim on this hub"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not submit claim"})
+			return
+		}
+
+		if admins, err := adminIDs(writeCtx, cfg); err != nil {
+			log.Printf("⚠️ could not notify admins of claim %s: %v", claim.ID.Hex(), err)
+		} else if len(admins) > 0 {
+			go utils.CreateNotification(cfg, admins,
+				"New ownership claim",
+				fmt.Sprintf("%s claimed %s and is waiting for review.", claim.BusinessName, hub.Title))
+		}
+
+		c.JSON(http.StatusCreated, claim)
+	}
+}
+
+// ---------------- LIST CLAIMS ----------------
+// ListClaims is the admin review queue, oldest first. ?status= defaults to
+// pending; ?status=all lists every claim.
+func ListClaims(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only admins can review claims"})
+			return
+		}
+		filter := bson.M{}
+		if status := c.DefaultQuery("status", models.ClaimStatusPending); status != "all" {
+			filter["status"] = status
+		}
+		listClaims(c, cfg, filter, 1)
+	}
+}
+
+// ListHubClaims lists a hub's claims: all of them for admins, the
+// caller's own for everyone else
+func ListHubClaims(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+		filter := bson.M{"hub_id": hubID}
+		if c.GetString("role") != "admin" {
+			userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+				return
+			}
+			filter["user_id"] = userID
+		}
+		listClaims(c, cfg, filter, -1)
+	}
+}
+
+// ListMyClaims lists the caller's claims, newest first
+func ListMyClaims(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+		listClaims(c, cfg, bson.M{"user_id": userID}, -1)
+	}
+}
+
+// ---------------- GET CLAIM ----------------
+// GetClaim returns a claim with its audit trail (claimant or admin)
+func GetClaim(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		claim, ok := loadClaim(c, cfg)
+		if !ok {
+			return
+		}
+		if c.GetString("role") != "admin" && claim.UserID.Hex() != c.GetString("user_id") {
+			c.JSON(http.StatusNotFound, gin.H{"error": "claim not found"})
+			return
+		}
+		claims := []models.HubClaim{claim}
+		enrichClaims(ctx, cfg, claims)
+		c.JSON(http.StatusOK, claims[0])
+	}
+}
+
+// ---------------- WITHDRAW CLAIM ----------------
+// WithdrawClaim lets the claimant take back a pending claim
+func WithdrawClaim(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		claim, ok := loadClaim(c, cfg)
+		if !ok {
+			return
+		}
+		if claim.UserID != userID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "claim not found"})
+			return
+		}
+
+		updated, err := transitionClaim(ctx, cfg, claim.ID, models.ClaimStatusPending, bson.M{"status": models.ClaimStatusWithdrawn},
+			models.ClaimEvent{Action: models.ClaimActionWithdrawn, ActorID: userID, At: time.Now()})
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusConflict, gin.H{"error": "only pending claims can be withdrawn"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not withdraw claim"})
+			return
+		}
+		c.JSON(http.StatusOK, updated)
+	}
+}
+
+// ---------------- APPROVE CLAIM ----------------
+// ApproveClaim (admin) transfers the hub to the claimant and marks it
+// verified. An earlier approved claim on the hub is revoked as superseded.
+func ApproveClaim(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID, ok := claimAdmin(c)
+		if !ok {
+			return
+		}
+		var input struct {
+			Note string `json:"note" binding:"max=1000"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) { // the body is optional
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		claim, ok := loadClaim(c, cfg)
+		if !ok {
+			return
+		}
+		db := cfg.MongoClient.Database(cfg.DBName)
+		var hub models.Hub
+		if err := db.Collection("hubs").FindOne(ctx, bson.M{"_id": claim.HubID, "merged_into": bson.M{"$exists": false}}).Decode(&hub); err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "the hub no longer exists"})
+			return
+		}
+
+		now := time.Now()
+		previousOwner := hub.UserID
+		if hub.Verified && hub.VerifiedClaimID != nil {
+			// keep the hand-back chain pointing at the original lister
+			var current models.HubClaim
+			if err := db.Collection("hub_claims").FindOne(ctx, bson.M{"_id": *hub.VerifiedClaimID}).Decode(&current); err == nil && current.PreviousOwnerID != nil {
+				previousOwner = *current.PreviousOwnerID
+			}
+		}
+
+		approved, err := transitionClaim(ctx, cfg, claim.ID, models.ClaimStatusPending, bson.M{
+			"status":            models.ClaimStatusApproved,
+			"previous_owner_id": previousOwner,
+			"reviewed_by":       adminID,
+			"reviewed_at":       now,
+			"decision_note":     input.Note,
+		}, models.ClaimEvent{Action: models.ClaimActionApproved, ActorID: adminID, Note: input.Note, At: now})
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusConflict, gin.H{"error": "only pending claims can be approved"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not approve claim"})
+			return
+		}
+
+		// --- Supersede the current owner's claim ---
+		if hub.Verified && hub.VerifiedClaimID != nil && *hub.VerifiedClaimID != claim.ID {
+			_, err := transitionClaim(ctx, cfg, *hub.VerifiedClaimID, models.ClaimStatusApproved, bson.M{"status": models.ClaimStatusRevoked},
+				models.ClaimEvent{Action: models.ClaimActionSuperseded, ActorID: adminID, Note: "claim " + claim.ID.Hex() + " was approved", At: now})
+			if err != nil && err != mongo.ErrNoDocuments {
+				log.Printf("⚠️ could not supersede claim %s: %v", hub.VerifiedClaimID.Hex(), err)
+			}
+			if err := clearOfficialPhotos(ctx, cfg, hub.ID); err != nil {
+				log.Printf("⚠️ could not clear official photos of hub %s: %v", hub.ID.Hex(), err)
+			}
+		}
+
+		// --- Transfer the hub ---
+		_, err = db.Collection("hubs").UpdateOne(ctx, bson.M{"_id": hub.ID}, bson.M{"$set": bson.M{
+			"user_id":           claim.UserID,
+			"verified":          true,
+			"verified_at":       now,
+			"verified_claim_id": claim.ID,
+			"updated_at":        now,
+		}})
+		if err != nil {
+			log.Printf("❌ claim %s approved but hub %s was not transferred: %v", claim.ID.Hex(), hub.ID.Hex(), err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "claim approved but the hub could not be transferred"})
+			return
+		}
+
+		go utils.CreateNotification(cfg, []primitive.ObjectID{claim.UserID},
+			"Ownership claim approved",
+			fmt.Sprintf("You are now the verified owner of %s.", hub.Title))
+		if hub.UserID != claim.UserID {
+			go utils.CreateNotification(cfg, []primitive.ObjectID{hub.UserID},
+				"Hub ownership transferred",
+				fmt.Sprintf("%s is now managed by its verified owner, %s.", hub.Title, claim.BusinessName))
+		}
+
+		c.JSON(http.StatusOK, approved)
+	}
+}
+
+// ---------------- REJECT CLAIM ----------------
+// RejectClaim (admin) turns down a pending claim; a note is required so the
+// claimant knows why
+func RejectClaim(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID, ok := claimAdmin(c)
+		if !ok {
+			return
+		}
+		var input struct {
+			Note string `json:"note" binding:"required,max=1000"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		claim, ok := loadClaim(c, cfg)
+		if !ok {
+			return
+		}
+		now := time.Now()
+		rejected, err := transitionClaim(ctx, cfg, claim.ID, models.ClaimStatusPending, bson.M{
+			"status":        models.ClaimStatusRejected,
+			"reviewed_by":   adminID,
+			"reviewed_at":   now,
+			"decision_note": input.Note,
+		}, models.ClaimEvent{Action: models.ClaimActionRejected, ActorID: adminID, Note: input.Note, At: now})
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusConflict, gin.H{"error": "only pending claims can be rejected"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not reject claim"})
+			return
+		}
+
+		go utils.CreateNotification(cfg, []primitive.ObjectID{claim.UserID},
+			"Ownership claim rejected",
+			fmt.Sprintf("Your claim as %s was rejected: %s", claim.BusinessName, input.Note))
+
+		c.JSON(http.StatusOK, rejected)
+	}
+}
+
+// ---------------- REVOKE CLAIM ----------------
+// RevokeClaim (admin) withdraws an approved claim: the hub loses its badge
+// and official photos and goes back to the owner it had before the claim
+func RevokeClaim(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID, ok := claimAdmin(c)
+		if !ok {
+			return
+		}
+		var input struct {
+			Note string `json:"note" binding:"required,max=1000"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		claim, ok := loadClaim(c, cfg)
+		if !ok {
+			return
+		}
+		now := time.Now()
+		revoked, err := transitionClaim(ctx, cfg, claim.ID, models.ClaimStatusApproved, bson.M{"status": models.ClaimStatusRevoked},
+			models.ClaimEvent{Action: models.ClaimActionRevoked, ActorID: adminID, Note: input.Note, At: now})
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusConflict, gin.H{"error": "only approved claims can be revoked"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not revoke claim"})
+			return
+		}
+
+		// Only touch the hub if this claim is still the one it rests on
+		set := bson.M{"verified": false, "updated_at": now}
+		if claim.PreviousOwnerID != nil {
+			set["user_id"] = *claim.PreviousOwnerID
+		}
+		res, err := cfg.MongoClient.Database(cfg.DBName).Collection("hubs").UpdateOne(ctx,
+			bson.M{"_id": claim.HubID, "verified_claim_id": claim.ID},
+			bson.M{"$set": set, "$unset": bson.M{"verified_at": "", "verified_claim_id": ""}})
+		if err != nil {
+			log.Printf("❌ claim %s revoked but hub %s was not reset: %v", claim.ID.Hex(), claim.HubID.Hex(), err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "claim revoked but the hub could not be reset"})
+			return
+		}
+		if res.ModifiedCount > 0 {
+			if err := clearOfficialPhotos(ctx, cfg, claim.HubID); err != nil {
+				log.Printf("⚠️ could not clear official photos of hub %s: %v", claim.HubID.Hex(), err)
+			}
+		}
+
+		go utils.CreateNotification(cfg, []primitive.ObjectID{claim.UserID},
+			"Ownership revoked",
+			fmt.Sprintf("Your verified ownership as %s was revoked: %s", claim.BusinessName, input.Note))
+
+		c.JSON(http.StatusOK, revoked)
+	}
+}
+
+// =============================
+// Helpers
+// =============================
+
+// claimAdmin returns the caller's id if they are an admin, writing the
+// error response otherwise
+func claimAdmin(c *gin.Context) (primitive.ObjectID, bool) {
+	if c.GetString("role") != "admin" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only admins can review claims"})
+		return primitive.NilObjectID, false
+	}
+	id, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// loadClaim resolves :claimId, writing the error response itself
+func loadClaim(c *gin.Context, cfg *config.Config) (models.HubClaim, bool) {
+	var claim models.HubClaim
+	id, err := primitive.ObjectIDFromHex(c.Param("claimId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid claim id"})
+		return claim, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = cfg.MongoClient.Database(cfg.DBName).Collection("hub_claims").FindOne(ctx, bson.M{"_id": id}).Decode(&claim)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "claim not found"})
+		return claim, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch claim"})
+		return claim, false
+	}
+	return claim, true
+}
+
+// transitionClaim moves a claim out of status from, appending event to its
+// audit trail. It returns mongo.ErrNoDocuments if the claim is no longer
+// in that status.
+func transitionClaim(ctx context.Context, cfg *config.Config, id primitive.ObjectID, from string, set bson.M, event models.ClaimEvent) (models.HubClaim, error) {
+	set["updated_at"] = event.At
+	var claim models.HubClaim
+	err := cfg.MongoClient.Database(cfg.DBName).Collection("hub_claims").FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "status": from},
+		bson.M{"$set": set, "$push": bson.M{"history": event}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&claim)
+	return claim, err
+}
+
+// listClaims writes a page of claims matching filter, by created_at in
+// the given direction
+func listClaims(c *gin.Context, cfg *config.Config, filter bson.M, direction int) {
+	page, limit := pagination(c, 20, 100)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	col := cfg.MongoClient.Database(cfg.DBName).Collection("hub_claims")
+	total, err := col.CountDocuments(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not count claims"})
+		return
+	}
+	cursor, err := col.Find(ctx, filter, options.Find().
+		SetSort(bson.M{"created_at": direction}).
+		SetSkip(int64((page-1)*limit)).
+		SetLimit(int64(limit)))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch claims"})
+		return
+	}
+	claims := []models.HubClaim{}
+	if err := cursor.All(ctx, &claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not decode claims"})
+		return
+	}
+	enrichClaims(ctx, cfg, claims)
+
+	c.JSON(http.StatusOK, gin.H{
+		"claims": claims,
+		"page":   page,
+		"limit":  limit,
+		"total":  total,
+	})
+}
+
+// enrichClaims fills in hub titles and claimant names
+func enrichClaims(ctx context.Context, cfg *config.Config, claims []models.HubClaim) {
+	db := cfg.MongoClient.Database(cfg.DBName)
+	titles := map[primitive.ObjectID]string{}
+	names := map[primitive.ObjectID]string{}
+	for i, cl := range claims {
+		title, ok := titles[cl.HubID]
+		if !ok {
+			var hub models.Hub
+			if err := db.Collection("hubs").FindOne(ctx, bson.M{"_id": cl.HubID}).Decode(&hub); err == nil {
+				title = hub.Title
+			}
+			titles[cl.HubID] = title
+		}
+		name, ok := names[cl.UserID]
+		if !ok {
+			var user models.User
+			if err := db.Collection("users").FindOne(ctx, bson.M{"_id": cl.UserID}).Decode(&user); err == nil {
+				name = user.Name
+			} else {
+				name = "Unknown User"
+			}
+			names[cl.UserID] = name
+		}
+		claims[i].HubTitle, claims[i].ClaimantName = title, name
+	}
+}
+
+// adminIDs lists every admin, for notifications
+func adminIDs(ctx context.Context, cfg *config.Config) ([]primitive.ObjectID, error) {
+	ids, err := cfg.MongoClient.Database(cfg.DBName).Collection("users").Distinct(ctx, "_id", bson.M{"role": "admin"})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if oid, ok := id.(primitive.ObjectID); ok {
+			out = append(out, oid)
+		}
+	}
+	return out, nil
+}
+
+// clearOfficialPhotos drops the official mark from a hub's photos when it
+// changes or loses its verified owner
+func clearOfficialPhotos(ctx context.Context, cfg *config.Config, hubID primitive.ObjectID) error {
+	_, err := cfg.MongoClient.Database(cfg.DBName).Collection("hub_photos").UpdateMany(ctx,
+		bson.M{"hub_id": hubID, "official": true},
+		bson.M{"$unset": bson.M{"official": ""}})
+	return err
+}
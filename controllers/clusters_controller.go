@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+)
+
+const (
+	// clusterMaxZoom is the zoom from which individual hubs are returned
+	clusterMaxZoom = 16
+	// clusterCellsPerTile is how many grid cells span one map tile, i.e.
+	// roughly one cluster per 64px of a 256px tile
+	clusterCellsPerTile = 4
+	// clusterMaxHubs caps the individual hubs returned for one view
+	clusterMaxHubs = 1000
+)
+
+// HubCluster is a grid cell of hubs. Single-hub cells carry the hub's id
+// and title so the map can draw a normal marker.
+type HubCluster struct {
+	Count    int                 `bson:"count" json:"count"`
+	Centroid models.Coordinates  `bson:"centroid" json:"centroid"`
+	HubID    *primitive.ObjectID `bson:"hub_id,omitempty" json:"hub_id,omitempty"`
+	Title    string              `bson:"title,omitempty" json:"title,omitempty"`
+}
+
+// mapBounds is a ?bbox=minLng,minLat,maxLng,maxLat viewport. MinLng may be
+// greater than MaxLng when the view crosses the antimeridian.
+type mapBounds struct {
+	MinLng, MinLat, MaxLng, MaxLat float64
+}
+
+// ---------------- CLUSTERS ----------------
+// ListHubClusters groups hubs in ?bbox= into grid cells sized for ?zoom=
+// (0-22), with a count and centroid per cell. From clusterMaxZoom on, it
+// returns the hubs themselves. The ListHubs filters apply.
+func ListHubClusters(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bounds, err := parseBounds(c.Query("bbox"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		zoom, err := strconv.Atoi(c.Query("zoom"))
+		if err != nil || zoom < 0 || zoom > 22 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "zoom must be a whole number from 0 to 22"})
+			return
+		}
+
+		filter, err := hubListFilter(c, cfg)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		for k, v := range bounds.filter() {
+			filter[k] = v
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		col := cfg.MongoClient.Database(cfg.DBName).Collection("hubs")
+
+		// --- Zoomed in: the hubs themselves ---
+		if zoom >= clusterMaxZoom {
+			cursor, err := col.Find(ctx, filter, options.Find().
+				SetProjection(bson.M{"title": 1, "coordinates": 1, "images": bson.M{"$slice": 1}, "wifi": 1, "favorites_count": 1}).
+				SetSort(bson.M{"_id": 1}).
+				SetLimit(clusterMaxHubs+1))
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch hubs"})
+				return
+			}
+			hubs := []models.Hub{}
+			if err := cursor.All(ctx, &hubs); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not decode hubs"})
+				return
+			}
+			truncated := len(hubs) > clusterMaxHubs
+			if truncated {
+				hubs = hubs[:clusterMaxHubs]
+			}
+			c.JSON(http.StatusOK, gin.H{
+				"mode":      "hubs",
+				"zoom":      zoom,
+				"hubs":      hubs,
+				"truncated": truncated,
+			})
+			return
+		}
+
+		// --- Zoomed out: grid cells anchored at -180,-90 so they don't
+		// shift while panning ---
+		cell := 360 / math.Pow(2, float64(zoom)) / clusterCellsPerTile
+		cellOf := func(field string, origin float64) bson.M {
+			return bson.M{"$floor": bson.M{"$divide": bson.A{bson.M{"$add": bson.A{"$" + field, origin}}, cell}}}
+		}
+
+		cursor, err := col.Aggregate(ctx, bson.A{
+			bson.M{"$match": filter},
+			bson.M{"$group": bson.M{
+				"_id":   bson.M{"x": cellOf("coordinates.lng", 180), "y": cellOf("coordinates.lat", 90)},
+				"count": bson.M{"$sum": 1},
+				"lat":   bson.M{"$avg": "$coordinates.lat"},
+				"lng":   bson.M{"$avg": "$coordinates.lng"},
+				"hub":   bson.M{"$first": "$_id"},
+				"title": bson.M{"$first": "$title"},
+			}},
+			bson.M{"$project": bson.M{
+				"_id":      0,
+				"count":    1,
+				"centroid": bson.M{"lat": "$lat", "lng": "$lng"},
+				"hub_id":   bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$count", 1}}, "$hub", "$$REMOVE"}},
+				"title":    bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$count", 1}}, "$title", "$$REMOVE"}},
+			}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "centroid.lat", Value: 1}, {Key: "centroid.lng", Value: 1}}},
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not cluster hubs"})
+			return
+		}
+		clusters := []HubCluster{}
+		if err := cursor.All(ctx, &clusters); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not decode clusters"})
+			return
+		}
+
+		total := 0
+		for _, cl := range clusters {
+			total += cl.Count
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"mode":          "clusters",
+			"zoom":          zoom,
+			"cell_size_deg": cell,
+			"clusters":      clusters,
+			"total":         total,
+		})
+	}
+}
+
+// =============================
+// Helpers
+// =============================
+
+func parseBounds(bbox string) (mapBounds, error) {
+	parts := strings.Split(bbox, ",")
+	if len(parts) != 4 {
+		return mapBounds{}, fmt.Errorf("bbox must be minLng,minLat,maxLng,maxLat")
+	}
+	var v [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return mapBounds{}, fmt.Errorf("bbox must be minLng,minLat,maxLng,maxLat")
+		}
+		v[i] = f
+	}
+	b := mapBounds{MinLng: v[0], MinLat: v[1], MaxLng: v[2], MaxLat: v[3]}
+	if b.MinLat < -90 || b.MaxLat > 90 || b.MinLat > b.MaxLat {
+		return mapBounds{}, fmt.Errorf("bbox latitudes must be within -90..90, south first")
+	}
+	if b.MinLng < -180 || b.MaxLng > 180 || b.MaxLng < -180 || b.MinLng > 180 {
+		return mapBounds{}, fmt.Errorf("bbox longitudes must be within -180..180")
+	}
+	return b, nil
+}
+
+// filter matches hubs inside the bounds. Hubs without a location (0,0)
+// are left out.
+func (b mapBounds) filter() bson.M {
+	f := bson.M{
+		"coordinates.lat": bson.M{"$gte": b.MinLat, "$lte": b.MaxLat},
+		"$nor":            bson.A{bson.M{"coordinates.lat": 0, "coordinates.lng": 0}},
+	}
+	if b.MinLng <= b.MaxLng {
+		f["coordinates.lng"] = bson.M{"$gte": b.MinLng, "$lte": b.MaxLng}
+	} else {
+		f["$or"] = bson.A{
+			bson.M{"coordinates.lng": bson.M{"$gte": b.MinLng}},
+			bson.M{"coordinates.lng": bson.M{"$lte": b.MaxLng}},
+		}
+	}
+	return f
+}
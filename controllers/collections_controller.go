@@ -0,0 +1,830 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+const (
+	// maxCollectionItems caps how many hubs one collection holds
+	maxCollectionItems = 500
+	// collectionSlugBytes is the entropy of share links
+	collectionSlugBytes = 16
+)
+
+// ---------------- CREATE COLLECTION ----------------
+func CreateCollection(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		var input struct {
+			Title       string `json:"title" binding:"required,max=120"`
+			Description string `json:"description" binding:"max=1000"`
+			Visibility  string `json:"visibility"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if input.Visibility == "" {
+			input.Visibility = models.CollectionPrivate
+		}
+		if !models.IsValidCollectionVisibility(input.Visibility) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "visibility must be one of private, unlisted, public"})
+			return
+		}
+		title := strings.TrimSpace(input.Title)
+		if title == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "title is required"})
+			return
+		}
+
+		slug, err := utils.RandomToken(collectionSlugBytes)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create share link"})
+			return
+		}
+
+		now := time.Now()
+		coll := models.Collection{
+			ID:            primitive.NewObjectID(),
+			OwnerID:       userID,
+			Title:         title,
+			Description:   strings.TrimSpace(input.Description),
+			Visibility:    input.Visibility,
+			Slug:          slug,
+			Collaborators: []primitive.ObjectID{},
+			Invited:       []primitive.ObjectID{},
+			Items:         []models.CollectionItem{},
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := cfg.MongoClient.Database(cfg.DBName).Collection("collections").InsertOne(ctx, coll); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create collection"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, coll)
+	}
+}
+
+// ---------------- LIST COLLECTIONS ----------------
+// ListCollections returns the collections the caller owns, edits or is
+// invited to. With ?public=true it lists public collections instead,
+// optionally for one ?owner=. Both are paginated (?page=&limit=).
+func ListCollections(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		filter := bson.M{"$or": bson.A{
+			bson.M{"owner_id": userID},
+			bson.M{"collaborators": userID},
+			bson.M{"invited": userID},
+		}}
+		if c.Query("public") == "true" {
+			filter = bson.M{"visibility": models.CollectionPublic}
+			if owner := c.Query("owner"); owner != "" {
+				ownerID, err := primitive.ObjectIDFromHex(owner)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid owner id"})
+					return
+				}
+				filter["owner_id"] = ownerID
+			}
+		}
+
+		page, limit := pagination(c, 20, 100)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		col := cfg.MongoClient.Database(cfg.DBName).Collection("collections")
+		opts := options.Find().
+			SetSort(bson.D{{Key: "updated_at", Value: -1}, {Key: "_id", Value: -1}}).
+			SetSkip(int64((page - 1) * limit)).
+			SetLimit(int64(limit))
+		cursor, err := col.Find(ctx, filter, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch collections"})
+			return
+		}
+		collections := []models.Collection{}
+		if err := cursor.All(ctx, &collections); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not decode collections"})
+			return
+		}
+
+		names := userNames(ctx, cfg)
+		for i := range collections {
+			collections[i].OwnerName = names(collections[i].OwnerID)
+			if !collections[i].IsEditor(userID) {
+				hideMembership(&collections[i])
+			}
+		}
+
+		total, _ := col.CountDocuments(ctx, filter)
+
+		c.JSON(http.StatusOK, gin.H{
+			"collections": collections,
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+		})
+	}
+}
+
+// ---------------- GET COLLECTION ----------------
+// GetCollection returns a collection with its hubs. Members and invitees
+// can see any collection; others only public ones.
+func GetCollection(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		coll, err := loadCollection(ctx, cfg, c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+			return
+		}
+		editor := coll.IsEditor(userID)
+		if !editor && !coll.IsInvited(userID) && coll.Visibility != models.CollectionPublic {
+			c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+			return
+		}
+
+		// editors keep items whose hub went away so they can tidy up
+		if err := enrichCollection(ctx, cfg, &coll, !editor); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load hubs"})
+			return
+		}
+		if !editor {
+			hideMembership(&coll)
+		}
+
+		c.JSON(http.StatusOK, coll)
+	}
+}
+
+// ---------------- SHARED COLLECTION ----------------
+// GetSharedCollection is the public, read-only view behind a share link.
+// Private collections are not served even with the right slug.
+func GetSharedCollection(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var coll models.Collection
+		err := cfg.MongoClient.Database(cfg.DBName).Collection("collections").FindOne(ctx, bson.M{
+			"slug":       c.Param("slug"),
+			"visibility": bson.M{"$in": bson.A{models.CollectionUnlisted, models.CollectionPublic}},
+		}).Decode(&coll)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+			return
+		}
+
+		if err := enrichCollection(ctx, cfg, &coll, true); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load hubs"})
+			return
+		}
+
+		// read-only view: no membership details
+		hideMembership(&coll)
+
+		c.JSON(http.StatusOK, coll)
+	}
+}
+
+// ---------------- UPDATE COLLECTION ----------------
+// UpdateCollection edits title and description (any editor) and
+// visibility (owner only)
+func UpdateCollection(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		var input struct {
+			Title       *string `json:"title" binding:"omitempty,max=120"`
+			Description *string `json:"description" binding:"omitempty,max=1000"`
+			Visibility  *string `json:"visibility"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		coll, err := loadCollection(ctx, cfg, c.Param("id"))
+		if err != nil || !coll.IsEditor(userID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+			return
+		}
+
+		update := bson.M{"updated_at": time.Now()}
+		if input.Title != nil {
+			title := strings.TrimSpace(*input.Title)
+			if title == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "title cannot be empty"})
+				return
+			}
+			update["title"] = title
+		}
+		if input.Description != nil {
+			update["description"] = strings.TrimSpace(*input.Description)
+		}
+		if input.Visibility != nil {
+			if coll.OwnerID != userID {
+				c.JSON(http.StatusForbidden, gin.H{"error": "only the owner can change who sees this collection"})
+				return
+			}
+			if !models.IsValidCollectionVisibility(*input.Visibility) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "visibility must be one of private, unlisted, public"})
+				return
+			}
+			update["visibility"] = *input.Visibility
+		}
+
+		col := cfg.MongoClient.Database(cfg.DBName).Collection("collections")
+		if _, err := col.UpdateOne(ctx, bson.M{"_id": coll.ID}, bson.M{"$set": update}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update collection"})
+			return
+		}
+		if err := col.FindOne(ctx, bson.M{"_id": coll.ID}).Decode(&coll); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load collection"})
+			return
+		}
+
+		c.JSON(http.StatusOK, coll)
+	}
+}
+
+// ---------------- DELETE COLLECTION ----------------
+func DeleteCollection(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		coll, err := loadCollection(ctx, cfg, c.Param("id"))
+		if err != nil || !coll.IsEditor(userID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+			return
+		}
+		if coll.OwnerID != userID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the owner can delete this collection"})
+			return
+		}
+
+		if _, err := cfg.MongoClient.Database(cfg.DBName).Collection("collections").DeleteOne(ctx, bson.M{"_id": coll.ID}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete collection"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Collection deleted successfully",
+			"id":      coll.ID.Hex(),
+		})
+	}
+}
+
+// ---------------- SHARE LINK ----------------
+// RotateCollectionSlug (owner only) replaces the share link, so anyone
+// holding the old one loses access
+func RotateCollectionSlug(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		coll, err := loadCollection(ctx, cfg, c.Param("id"))
+		if err != nil || coll.OwnerID != userID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+			return
+		}
+
+		slug, err := utils.RandomToken(collectionSlugBytes)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create share link"})
+			return
+		}
+		_, err = cfg.MongoClient.Database(cfg.DBName).Collection("collections").UpdateOne(ctx,
+			bson.M{"_id": coll.ID},
+			bson.M{"$set": bson.M{"slug": slug, "updated_at": time.Now()}},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update share link"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"slug": slug})
+	}
+}
+
+// ---------------- ITEMS ----------------
+// AddCollectionItem adds a hub, at the end or at ?position
+func AddCollectionItem(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		var input struct {
+			HubID    string `json:"hub_id" binding:"required"`
+			Note     string `json:"note" binding:"max=500"`
+			Position *int   `json:"position" binding:"omitempty,min=0"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		hubID, err := primitive.ObjectIDFromHex(input.HubID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		coll, err := loadCollection(ctx, cfg, c.Param("id"))
+		if err != nil || !coll.IsEditor(userID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+			return
+		}
+
+		db := cfg.MongoClient.Database(cfg.DBName)
+		err = db.Collection("hubs").FindOne(ctx, bson.M{
+			"_id":         hubID,
+			"merged_into": bson.M{"$exists": false},
+			"hidden":      bson.M{"$ne": true},
+		}).Err()
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+
+		now := time.Now()
+		item := models.CollectionItem{
+			HubID:   hubID,
+			Note:    strings.TrimSpace(input.Note),
+			AddedBy: userID,
+			AddedAt: now,
+		}
+		push := bson.M{"$each": bson.A{item}}
+		if input.Position != nil {
+			push["$position"] = *input.Position
+		}
+
+		// the filter rejects duplicates and full collections atomically
+		res, err := db.Collection("collections").UpdateOne(ctx,
+			bson.M{
+				"_id":          coll.ID,
+				"items.hub_id": bson.M{"$ne": hubID},
+				fmt.Sprintf("items.%d", maxCollectionItems-1): bson.M{"$exists": false},
+			},
+			bson.M{"$push": bson.M{"items": push}, "$set": bson.M{"updated_at": now}},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not add hub"})
+			return
+		}
+		if res.MatchedCount == 0 {
+			for _, it := range coll.Items {
+				if it.HubID == hubID {
+					c.JSON(http.StatusConflict, gin.H{"error": "hub is already in this collection"})
+					return
+				}
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("a collection holds at most %d hubs", maxCollectionItems)})
+			return
+		}
+
+		c.JSON(http.StatusCreated, item)
+	}
+}
+
+// UpdateCollectionItem changes the note on a hub in the collection
+func UpdateCollectionItem(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+		hubID, err := primitive.ObjectIDFromHex(c.Param("hubId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		var input struct {
+			Note string `json:"note" binding:"max=500"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		coll, err := loadCollection(ctx, cfg, c.Param("id"))
+		if err != nil || !coll.IsEditor(userID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+			return
+		}
+
+		res, err := cfg.MongoClient.Database(cfg.DBName).Collection("collections").UpdateOne(ctx,
+			bson.M{"_id": coll.ID, "items.hub_id": hubID},
+			bson.M{"$set": bson.M{"items.$.note": strings.TrimSpace(input.Note), "updated_at": time.Now()}},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update note"})
+			return
+		}
+		if res.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub is not in this collection"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"hub_id": hubID.Hex(),
+			"note":   strings.TrimSpace(input.Note),
+		})
+	}
+}
+
+// RemoveCollectionItem takes a hub out of the collection
+func RemoveCollectionItem(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+		hubID, err := primitive.ObjectIDFromHex(c.Param("hubId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		coll, err := loadCollection(ctx, cfg, c.Param("id"))
+		if err != nil || !coll.IsEditor(userID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+			return
+		}
+
+		res, err := cfg.MongoClient.Database(cfg.DBName).Collection("collections").UpdateOne(ctx,
+			bson.M{"_id": coll.ID, "items.hub_id": hubID},
+			bson.M{"$pull": bson.M{"items": bson.M{"hub_id": hubID}}, "$set": bson.M{"updated_at": time.Now()}},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not remove hub"})
+			return
+		}
+		if res.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub is not in this collection"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Hub removed from collection",
+			"hub_id":  hubID.Hex(),
+		})
+	}
+}
+
+// ReorderCollectionItems sets the display order. hub_ids must list every
+// hub in the collection exactly once.
+func ReorderCollectionItems(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		var input struct {
+			HubIDs []string `json:"hub_ids" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		coll, err := loadCollection(ctx, cfg, c.Param("id"))
+		if err != nil || !coll.IsEditor(userID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+			return
+		}
+
+		byHub := map[string]models.CollectionItem{}
+		for _, it := range coll.Items {
+			byHub[it.HubID.Hex()] = it
+		}
+		if len(input.HubIDs) != len(coll.Items) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "hub_ids must list every hub in the collection"})
+			return
+		}
+		ordered := make([]models.CollectionItem, 0, len(input.HubIDs))
+		for _, id := range input.HubIDs {
+			it, ok := byHub[id]
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "hub_ids must list every hub in the collection once", "hub_id": id})
+				return
+			}
+			delete(byHub, id)
+			ordered = append(ordered, it)
+		}
+
+		// only apply if nobody changed the collection since we read it
+		res, err := cfg.MongoClient.Database(cfg.DBName).Collection("collections").UpdateOne(ctx,
+			bson.M{"_id": coll.ID, "updated_at": coll.UpdatedAt},
+			bson.M{"$set": bson.M{"items": ordered, "updated_at": time.Now()}},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not reorder collection"})
+			return
+		}
+		if res.MatchedCount == 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "collection changed meanwhile, reload and try again"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"items": ordered})
+	}
+}
+
+// ---------------- COLLABORATORS ----------------
+// InviteCollaborator (owner only) invites a user by email to edit the
+// collection. They become a collaborator once they accept.
+func InviteCollaborator(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		var input struct {
+			Email string `json:"email" binding:"required,email"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		coll, err := loadCollection(ctx, cfg, c.Param("id"))
+		if err != nil || !coll.IsEditor(userID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+			return
+		}
+		if coll.OwnerID != userID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the owner can invite collaborators"})
+			return
+		}
+
+		db := cfg.MongoClient.Database(cfg.DBName)
+		var invitee models.User
+		if err := db.Collection("users").FindOne(ctx, bson.M{"email": input.Email}).Decode(&invitee); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no user with that email"})
+			return
+		}
+		if coll.IsEditor(invitee.ID) {
+			c.JSON(http.StatusConflict, gin.H{"error": "user can already edit this collection"})
+			return
+		}
+
+		_, err = db.Collection("collections").UpdateOne(ctx,
+			bson.M{"_id": coll.ID},
+			bson.M{"$addToSet": bson.M{"invited": invitee.ID}, "$set": bson.M{"updated_at": time.Now()}},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not invite user"})
+			return
+		}
+
+		if !coll.IsInvited(invitee.ID) {
+			go utils.CreateNotification(cfg, []primitive.ObjectID{invitee.ID},
+				"Collection invite",
+				fmt.Sprintf("You've been invited to edit the collection \"%s\".", coll.Title))
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "invite sent",
+			"user_id": invitee.ID.Hex(),
+		})
+	}
+}
+
+// AcceptCollectionInvite makes an invited caller a collaborator
+func AcceptCollectionInvite(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+		collID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collection id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		res, err := cfg.MongoClient.Database(cfg.DBName).Collection("collections").UpdateOne(ctx,
+			bson.M{"_id": collID, "invited": userID},
+			bson.M{
+				"$pull":     bson.M{"invited": userID},
+				"$addToSet": bson.M{"collaborators": userID},
+				"$set":      bson.M{"updated_at": time.Now()},
+			},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not accept invite"})
+			return
+		}
+		if res.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "no pending invite for this collection"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "you can now edit this collection"})
+	}
+}
+
+// RemoveCollaborator removes a collaborator or cancels an invite. The
+// owner can remove anyone; others can only remove themselves.
+func RemoveCollaborator(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+		memberID, err := primitive.ObjectIDFromHex(c.Param("userId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		coll, err := loadCollection(ctx, cfg, c.Param("id"))
+		if err != nil || (!coll.IsEditor(userID) && !coll.IsInvited(userID)) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "collection not found"})
+			return
+		}
+		if coll.OwnerID != userID && memberID != userID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the owner can remove other collaborators"})
+			return
+		}
+		if memberID == coll.OwnerID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "the owner cannot leave their own collection"})
+			return
+		}
+
+		res, err := cfg.MongoClient.Database(cfg.DBName).Collection("collections").UpdateOne(ctx,
+			bson.M{"_id": coll.ID},
+			bson.M{
+				"$pull": bson.M{"collaborators": memberID, "invited": memberID},
+				"$set":  bson.M{"updated_at": time.Now()},
+			},
+		)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not remove collaborator"})
+			return
+		}
+		if res.ModifiedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "user is not a collaborator"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "collaborator removed",
+			"user_id": memberID.Hex(),
+		})
+	}
+}
+
+// =============================
+// Helpers
+// =============================
+
+// hideMembership strips what only editors may see: the share slug (which
+// would outlive a switch away from public) and who is a member
+func hideMembership(coll *models.Collection) {
+	coll.Slug = ""
+	coll.Collaborators = nil
+	coll.Invited = nil
+}
+
+func loadCollection(ctx context.Context, cfg *config.Config, idHex string) (models.Collection, error) {
+	var coll models.Collection
+	id, err := primitive.ObjectIDFromHex(idHex)
+	if err != nil {
+		return coll, err
+	}
+	err = cfg.MongoClient.Database(cfg.DBName).Collection("collections").FindOne(ctx, bson.M{"_id": id}).Decode(&coll)
+	return coll, err
+}
+
+// enrichCollection attaches the visible hubs to the items and the owner's
+// name. With dropMissing, items whose hub is gone or hidden are left out.
+func enrichCollection(ctx context.Context, cfg *config.Config, coll *models.Collection, dropMissing bool) error {
+	ids := make([]primitive.ObjectID, 0, len(coll.Items))
+	for _, it := range coll.Items {
+		ids = append(ids, it.HubID)
+	}
+
+	hubs := []models.Hub{}
+	if len(ids) > 0 {
+		cursor, err := cfg.MongoClient.Database(cfg.DBName).Collection("hubs").Find(ctx, bson.M{
+			"_id":         bson.M{"$in": ids},
+			"merged_into": bson.M{"$exists": false},
+			"hidden":      bson.M{"$ne": true},
+		})
+		if err != nil {
+			return err
+		}
+		if err := cursor.All(ctx, &hubs); err != nil {
+			return err
+		}
+	}
+	byID := map[primitive.ObjectID]models.Hub{}
+	for _, h := range hubs {
+		byID[h.ID] = h
+	}
+
+	items := make([]models.CollectionItem, 0, len(coll.Items))
+	for _, it := range coll.Items {
+		if h, ok := byID[it.HubID]; ok {
+			it.Hub = &h
+		} else if dropMissing {
+			continue
+		}
+		items = append(items, it)
+	}
+	coll.Items = items
+	coll.OwnerName = userNames(ctx, cfg)(coll.OwnerID)
+	return nil
+}
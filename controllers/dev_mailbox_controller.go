@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+var devMailboxPage = template.Must(template.New("mailbox").Parse(`<!doctype html>
+<html>
+<head><meta charset="utf-8"><title>Dev mailbox</title></head>
+<body style="font-family: Arial, sans-serif; max-width: 900px; margin: 20px auto;">
+  <h2>Dev mailbox ({{len .}})</h2>
+  <table cellpadding="6" style="width: 100%; border-collapse: collapse;">
+    <tr style="text-align: left; background: #f1f1f1;"><th>Sent</th><th>To</th><th>Subject</th></tr>
+    {{range .}}
+    <tr style="border-bottom: 1px solid #eee;">
+      <td>{{.SentAt.Format "2006-01-02 15:04:05"}}</td>
+      <td>{{range .To}}{{if .Name}}{{.Name}} {{end}}&lt;{{.Address}}&gt; {{end}}</td>
+      <td><a href="/dev/mailbox/{{.ID}}">{{.Subject}}</a></td>
+    </tr>
+    {{else}}
+    <tr><td colspan="3">No messages yet.</td></tr>
+    {{end}}
+  </table>
+</body>
+</html>`))
+
+// ---------------- DEV MAILBOX ----------------
+// ListDevMailbox renders the messages captured by the dev mail transport.
+// JSON is returned when the client asks for it.
+func ListDevMailbox() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		box := utils.DevMailbox()
+		if box == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "dev mailbox disabled"})
+			return
+		}
+
+		messages := box.Messages()
+		if c.NegotiateFormat(gin.MIMEHTML, gin.MIMEJSON) == gin.MIMEJSON {
+			c.JSON(http.StatusOK, messages)
+			return
+		}
+
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		if err := devMailboxPage.Execute(c.Writer, messages); err != nil {
+			c.Status(http.StatusInternalServerError)
+		}
+	}
+}
+
+// GetDevMail shows a single captured message, HTML part preferred
+func GetDevMail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		box := utils.DevMailbox()
+		if box == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "dev mailbox disabled"})
+			return
+		}
+
+		for _, m := range box.Messages() {
+			if m.ID != c.Param("id") {
+				continue
+			}
+			if m.HTML != "" {
+				c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(m.HTML))
+			} else {
+				c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(m.Text))
+			}
+			return
+		}
+
+		c.JSON(http.StatusNotFound, gin.H{"error": "message not found"})
+	}
+}
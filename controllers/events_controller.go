@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"context"
+	"log"
 	"net/http"
 	"time"
 
@@ -63,35 +64,21 @@ func CreateEvent(cfg *config.Config) gin.HandlerFunc {
 
 
 		// --- Handle file uploads ---
-		form, err := c.MultipartForm()
-		if err != nil && err != http.ErrNotMultipart {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid form data"})
+		uploads, ok := imageUploads(c, "images", utils.ImageLimits{ // key must be "images"
+			MaxFileBytes: cfg.Uploads.MaxFileBytes,
+			MaxFiles:     cfg.Uploads.MaxEventImages,
+		})
+		if !ok {
 			return
 		}
 
-		var imageURLs []string
-		if form != nil {
-			files := form.File["images"] // key must be "images"
-			for _, fileHeader := range files {
-				file, err := fileHeader.Open()
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open file"})
-					return
-				}
-
-				url, err := utils.UploadToCloudinary(file, fileHeader)
-				file.Close()
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{
-						"error":   "image upload failed",
-						"details": err.Error(),
-						"file":    fileHeader.Filename,
-					})
-					return
-				}
-
-				imageURLs = append(imageURLs, url)
-			}
+		images, results, err := utils.UploadImages(cfg, "events", uploads, userID)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error":   "image upload failed",
+				"uploads": results,
+			})
+			return
 		}
 
 		// --- Save event ---
@@ -105,7 +92,7 @@ func CreateEvent(cfg *config.Config) gin.HandlerFunc {
 			TargetAmount: input.TargetAmount,
 			Deadline:     deadline,
 			Status:       "ACTIVE",
-			Images:       imageURLs,
+			Images:       images,
 			CreatedAt:    now,
 			UpdatedAt:    now,
 		}
@@ -115,10 +102,12 @@ func CreateEvent(cfg *config.Config) gin.HandlerFunc {
 		defer cancel()
 
 		if _, err := col.InsertOne(ctx, event); err != nil {
+			utils.RollbackUploads(cfg, images)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create event"})
 			return
 		}
 
+		event.Uploads = results
 		c.JSON(http.StatusCreated, event)
 	}
 }
@@ -270,7 +259,7 @@ func UpdateEvent(cfg *config.Config) gin.HandlerFunc {
 			TargetAmount float64  `form:"target_amount"`
 			Deadline     *string  `form:"deadline"`
 			Status       string   `form:"status"`
-			Images       []string `form:"images"` // existing image URLs to keep
+			Images       []string `form:"images"` // existing image IDs or URLs to keep
 		}
 
 		if err := c.ShouldBind(&input); err != nil {
@@ -316,30 +305,32 @@ func UpdateEvent(cfg *config.Config) gin.HandlerFunc {
 			update["deadline"] = parsed
 		}
 
+		// ✅ Existing images to keep (all of them unless the client lists some)
+		kept := existing.Images
+		if input.Images != nil {
+			kept = keepImages(existing.Images, input.Images)
+		}
+
 		// ✅ Handle new image uploads (multipart form)
-		newImageURLs := []string{}
-		form, _ := c.MultipartForm()
-		if form != nil {
-			files := form.File["new_images"] // key = "new_images"
-			for _, fileHeader := range files {
-				file, err := fileHeader.Open()
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open image"})
-					return
-				}
-				url, err := utils.UploadToCloudinary(file, fileHeader)
-				file.Close()
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "image upload failed", "details": err.Error()})
-					return
-				}
-				newImageURLs = append(newImageURLs, url)
-			}
+		uploads, ok := imageUploads(c, "new_images", utils.ImageLimits{ // key = "new_images"
+			MaxFileBytes: cfg.Uploads.MaxFileBytes,
+			MaxFiles:     cfg.Uploads.MaxEventImages,
+			Existing:     len(kept),
+		})
+		if !ok {
+			return
 		}
 
-		// ✅ Merge images (keep provided + add new)
-		if input.Images != nil || len(newImageURLs) > 0 {
-			update["images"] = append(input.Images, newImageURLs...)
+		uploaderID, _ := primitive.ObjectIDFromHex(requesterID)
+		newImages, results, err := utils.UploadImages(cfg, "events", uploads, uploaderID)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "image upload failed", "uploads": results})
+			return
+		}
+
+		// ✅ Merge images (keep listed existing ones + add new)
+		if input.Images != nil || len(newImages) > 0 {
+			update["images"] = append(kept, newImages...)
 		}
 
 		// ❗ Reject empty update
@@ -348,16 +339,20 @@ func UpdateEvent(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		// ✅ Apply update
-		_, err = col.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": update})
+		// ✅ Apply update (fresh timeout, uploads may have used up ctx)
+		writeCtx, writeCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer writeCancel()
+
+		_, err = col.UpdateOne(writeCtx, bson.M{"_id": objID}, bson.M{"$set": update})
 		if err != nil {
+			utils.RollbackUploads(cfg, newImages)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update event"})
 			return
 		}
 
 		// ✅ Fetch updated event
 		var updated models.Event
-		if err := col.FindOne(ctx, bson.M{"_id": objID}).Decode(&updated); err != nil {
+		if err := col.FindOne(writeCtx, bson.M{"_id": objID}).Decode(&updated); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve updated event"})
 			return
 		}
@@ -365,6 +360,7 @@ func UpdateEvent(cfg *config.Config) gin.HandlerFunc {
 		c.JSON(http.StatusOK, gin.H{
 			"message": "Event updated successfully",
 			"event":   updated,
+			"uploads": results,
 		})
 	}
 }
@@ -416,9 +412,12 @@ func DeleteEvent(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		// 🔹 (Optional) TODO: Delete images from Cloudinary
+		// 🔹 Delete images from storage; anything that fails stays recorded
+		// and is retried by the asset reconciler
 		for _, img := range existing.Images {
-			  utils.DeleteFromCloudinary(img)
+			if err := utils.DeleteAsset(cfg, img); err != nil {
+				log.Printf("⚠️ could not delete image %s: %v", img.URL, err)
+			}
 		}
 
 		c.JSON(http.StatusOK, gin.H{
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+const (
+	// exportUserLimit caps exports for everyone but admins
+	exportUserLimit = 500
+	// exportFlushEvery is how many hubs are rated and written between flushes
+	exportFlushEvery = 100
+	// exportBatchSize is how many hubs the cursor fetches per round trip
+	exportBatchSize = 200
+)
+
+// ---------------- EXPORT ----------------
+// ExportHubs streams hubs as ?format=geojson|csv|kml. The ListHubs filters
+// apply. Hubs are read from a cursor and written as they arrive, so the
+// export is never held in memory. Admins get every match; others get the
+// first exportUserLimit.
+func ExportHubs(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		exporter, err := utils.NewHubExporter(c.DefaultQuery("format", utils.ExportGeoJSON), c.Writer)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		filter, err := hubListFilter(c, cfg)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		opts := options.Find().
+			SetProjection(bson.M{"images": bson.M{"$slice": 1}}).
+			SetSort(bson.M{"_id": 1}).
+			SetBatchSize(exportBatchSize)
+		full := c.GetString("role") == "admin"
+		if !full {
+			opts.SetLimit(exportUserLimit)
+			c.Header("X-Export-Limit", strconv.Itoa(exportUserLimit))
+		}
+
+		cursor, err := cfg.MongoClient.Database(cfg.DBName).Collection("hubs").Find(ctx, filter, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch hubs"})
+			return
+		}
+		defer cursor.Close(ctx)
+
+		filename := fmt.Sprintf("hubs-%s.%s", time.Now().UTC().Format("20060102"), exporter.Extension())
+		c.Header("Content-Type", exporter.ContentType())
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Status(http.StatusOK)
+
+		// Headers are sent from here on; failures can only be logged and
+		// leave a truncated file
+		if err := exporter.Begin(); err != nil {
+			log.Printf("⚠️ Hub export: %v", err)
+			return
+		}
+		// Ratings are looked up per batch of hubs, written, then flushed
+		n := 0
+		batch := make([]models.Hub, 0, exportFlushEvery)
+		writeBatch := func() bool {
+			ratings, err := hubRatings(ctx, cfg, batch)
+			if err != nil {
+				log.Printf("❌ Hub export: could not load ratings after %d hubs: %v", n, err)
+				return false
+			}
+			for _, hub := range batch {
+				r := ratings[hub.ID]
+				if err := exporter.Write(utils.HubExportRow{Hub: hub, AvgRating: r.Avg, Reviews: r.Count}); err != nil {
+					log.Printf("⚠️ Hub export: client went away after %d hubs: %v", n, err)
+					return false
+				}
+				n++
+			}
+			batch = batch[:0]
+			c.Writer.Flush()
+			return true
+		}
+		for cursor.Next(ctx) {
+			var hub models.Hub
+			if err := cursor.Decode(&hub); err != nil {
+				log.Printf("⚠️ Hub export: skipping undecodable hub: %v", err)
+				continue
+			}
+			batch = append(batch, hub)
+			if len(batch) == exportFlushEvery && !writeBatch() {
+				return
+			}
+		}
+		if err := cursor.Err(); err != nil {
+			log.Printf("❌ Hub export: cursor failed after %d hubs: %v", n, err)
+			return
+		}
+		if len(batch) > 0 && !writeBatch() {
+			return
+		}
+		if err := exporter.End(); err != nil {
+			log.Printf("⚠️ Hub export: %v", err)
+			return
+		}
+		c.Writer.Flush()
+	}
+}
@@ -0,0 +1,512 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+// ---------------- SUGGEST EDIT ----------------
+// SuggestHubEdit lets any user propose new values for a hub's fields.
+// Only the fields present in the body are considered.
+func SuggestHubEdit(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		var input struct {
+			Title        *string                 `json:"title"`
+			Description  *string                 `json:"description"`
+			Lat          *float64                `json:"lat" binding:"omitempty,min=-90,max=90"`
+			Lng          *float64                `json:"lng" binding:"omitempty,min=-180,max=180"`
+			LocationName *string                 `json:"location_name"`
+			Hours        *[]models.OpeningPeriod `json:"hours"`
+			Status       *string                 `json:"status"`
+			Note         string                  `json:"note" binding:"max=500"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if input.Title != nil && *input.Title == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "title cannot be empty"})
+			return
+		}
+		if input.Hours != nil {
+			if err := validateOpeningHours(*input.Hours); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		if input.Status != nil && !models.IsValidHubStatus(*input.Status) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "status must be one of open, temporarily_closed, permanently_closed"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var hub models.Hub
+		if err := cfg.MongoClient.Database(cfg.DBName).Collection("hubs").FindOne(ctx, bson.M{"_id": hubID}).Decode(&hub); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+
+		current := hub.Fields()
+		proposed := current
+		if input.Title != nil {
+			proposed.Title = *input.Title
+		}
+		if input.Description != nil {
+			proposed.Description = *input.Description
+		}
+		if input.Lat != nil {
+			proposed.Coordinates.Lat = *input.Lat
+		}
+		if input.Lng != nil {
+			proposed.Coordinates.Lng = *input.Lng
+		}
+		if input.LocationName != nil {
+			proposed.LocationName = *input.LocationName
+		}
+		if input.Hours != nil {
+			proposed.Hours = *input.Hours
+		}
+		if input.Status != nil {
+			proposed.Status = *input.Status
+		}
+		if input.Lat != nil || input.Lng != nil {
+			if err := utils.ValidateCoordinates(proposed.Coordinates); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		changes := current.Diff(proposed)
+		if len(changes) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "suggestion does not change anything"})
+			return
+		}
+
+		edit := models.HubEdit{
+			ID:        primitive.NewObjectID(),
+			HubID:     hubID,
+			UserID:    userID,
+			Changes:   changes,
+			Note:      input.Note,
+			Status:    models.EditStatusPending,
+			CreatedAt: time.Now(),
+		}
+		if _, err := cfg.MongoClient.Database(cfg.DBName).Collection("hub_edits").InsertOne(ctx, edit); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save suggestion"})
+			return
+		}
+
+		if hub.UserID != userID {
+			go utils.CreateNotification(cfg, []primitive.ObjectID{hub.UserID},
+				"Edit suggested",
+				fmt.Sprintf("Someone suggested changes to %s. Review them to keep the listing up to date.", hub.Title))
+		}
+
+		c.JSON(http.StatusCreated, edit)
+	}
+}
+
+// ---------------- LIST EDITS ----------------
+// ListHubEdits returns suggestions for a hub (?status=, default pending).
+// Owners and moderators see everyone's, other users only their own.
+func ListHubEdits(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		db := cfg.MongoClient.Database(cfg.DBName)
+		var hub models.Hub
+		if err := db.Collection("hubs").FindOne(ctx, bson.M{"_id": hubID}).Decode(&hub); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+
+		filter := bson.M{"hub_id": hubID, "status": c.DefaultQuery("status", models.EditStatusPending)}
+		if !canManageHub(c, hub) {
+			userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+				return
+			}
+			filter["user_id"] = userID
+		}
+
+		cursor, err := db.Collection("hub_edits").Find(ctx, filter, options.Find().SetSort(bson.M{"created_at": -1}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch suggestions"})
+			return
+		}
+		edits := []models.HubEdit{}
+		if err := cursor.All(ctx, &edits); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not decode suggestions"})
+			return
+		}
+
+		names := userNames(ctx, cfg)
+		for i := range edits {
+			edits[i].UserName = names(edits[i].UserID)
+		}
+
+		c.JSON(http.StatusOK, edits)
+	}
+}
+
+// ---------------- REVIEW EDIT ----------------
+// ReviewHubEdit accepts or rejects a pending suggestion. Accepting fails
+// with 409 if any of its fields changed since it was proposed.
+func ReviewHubEdit(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reviewerID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+		editID, err := primitive.ObjectIDFromHex(c.Param("editId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid edit id"})
+			return
+		}
+
+		var input struct {
+			Status string `json:"status" binding:"required,oneof=accepted rejected"`
+			Note   string `json:"note" binding:"max=500"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		db := cfg.MongoClient.Database(cfg.DBName)
+		var hub models.Hub
+		if err := db.Collection("hubs").FindOne(ctx, bson.M{"_id": hubID}).Decode(&hub); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+		if !canManageHub(c, hub) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		var edit models.HubEdit
+		if err := db.Collection("hub_edits").FindOne(ctx, bson.M{"_id": editID, "hub_id": hubID}).Decode(&edit); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "suggestion not found"})
+			return
+		}
+		if edit.Status != models.EditStatusPending {
+			c.JSON(http.StatusConflict, gin.H{"error": "suggestion was already " + edit.Status})
+			return
+		}
+
+		if input.Status == models.EditStatusAccepted {
+			if stale := hub.Fields().Conflicts(edit.Changes); len(stale) > 0 {
+				c.JSON(http.StatusConflict, gin.H{"error": "hub changed since this suggestion was made", "fields": stale})
+				return
+			}
+		}
+
+		// Claim the suggestion first so two reviewers can't both apply it
+		now := time.Now()
+		edit.Status = input.Status
+		edit.ReviewedBy = &reviewerID
+		edit.ReviewNote = input.Note
+		edit.ReviewedAt = &now
+		edits := db.Collection("hub_edits")
+		res, err := edits.UpdateOne(ctx, bson.M{"_id": edit.ID, "status": models.EditStatusPending}, bson.M{"$set": bson.M{
+			"status":      edit.Status,
+			"reviewed_by": reviewerID,
+			"review_note": edit.ReviewNote,
+			"reviewed_at": now,
+		}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update suggestion"})
+			return
+		}
+		if res.MatchedCount == 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "suggestion was already reviewed"})
+			return
+		}
+
+		var revision *models.HubRevision
+		if input.Status == models.EditStatusAccepted {
+			rev, err := applyHubFields(ctx, cfg, hub, hub.Fields().Apply(edit.Changes), edit.UserID, models.RevisionSourceSuggestion, &edit.ID, nil)
+			if err != nil {
+				// hand the suggestion back to the queue
+				edits.UpdateOne(ctx, bson.M{"_id": edit.ID}, bson.M{
+					"$set":   bson.M{"status": models.EditStatusPending},
+					"$unset": bson.M{"reviewed_by": "", "review_note": "", "reviewed_at": ""},
+				})
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not apply suggestion"})
+				return
+			}
+			revision = &rev
+		}
+
+		if edit.UserID != reviewerID {
+			go utils.CreateNotification(cfg, []primitive.ObjectID{edit.UserID},
+				"Your suggestion was "+edit.Status,
+				fmt.Sprintf("Your suggested edit to %s was %s.", hub.Title, edit.Status))
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"edit":     edit,
+			"revision": revision,
+		})
+	}
+}
+
+// ---------------- HISTORY ----------------
+// GetHubHistory lists a hub's revisions, newest first (?page=&limit=)
+func GetHubHistory(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		page, limit := pagination(c, 20, 100)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		col := cfg.MongoClient.Database(cfg.DBName).Collection("hub_revisions")
+		opts := options.Find().
+			SetSort(bson.M{"number": -1}).
+			SetSkip(int64((page - 1) * limit)).
+			SetLimit(int64(limit))
+		cursor, err := col.Find(ctx, bson.M{"hub_id": hubID}, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch history"})
+			return
+		}
+		revisions := []models.HubRevision{}
+		if err := cursor.All(ctx, &revisions); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not decode history"})
+			return
+		}
+
+		names := userNames(ctx, cfg)
+		for i := range revisions {
+			revisions[i].AuthorName = names(revisions[i].AuthorID)
+		}
+
+		total, _ := col.CountDocuments(ctx, bson.M{"hub_id": hubID})
+
+		c.JSON(http.StatusOK, gin.H{
+			"revisions": revisions,
+			"page":      page,
+			"limit":     limit,
+			"total":     total,
+		})
+	}
+}
+
+// ---------------- REVERT ----------------
+// RevertHub restores the field values recorded by a revision. The revert
+// is itself a new revision, so it can be undone the same way.
+func RevertHub(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+		revisionID, err := primitive.ObjectIDFromHex(c.Param("revisionId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		db := cfg.MongoClient.Database(cfg.DBName)
+		var hub models.Hub
+		if err := db.Collection("hubs").FindOne(ctx, bson.M{"_id": hubID}).Decode(&hub); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+		if !canManageHub(c, hub) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		var target models.HubRevision
+		if err := db.Collection("hub_revisions").FindOne(ctx, bson.M{"_id": revisionID, "hub_id": hubID}).Decode(&target); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "revision not found"})
+			return
+		}
+		if len(hub.Fields().Diff(target.Snapshot)) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "hub already matches this revision"})
+			return
+		}
+
+		revision, err := applyHubFields(ctx, cfg, hub, target.Snapshot, userID, models.RevisionSourceRevert, nil, &target.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not revert hub"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":  fmt.Sprintf("Hub reverted to revision %d", target.Number),
+			"revision": revision,
+		})
+	}
+}
+
+// =============================
+// Helpers
+// =============================
+
+// applyHubFields writes next onto the hub and records the change
+func applyHubFields(ctx context.Context, cfg *config.Config, hub models.Hub, next models.HubFields, author primitive.ObjectID, source string, editID, revertOf *primitive.ObjectID) (models.HubRevision, error) {
+	set := bson.M{
+		"title":       next.Title,
+		"description": next.Description,
+		"coordinates": next.Coordinates,
+		"location":    next.LocationName,
+		"hours":       next.Hours,
+		"status":      next.Status,
+		"updated_at":  time.Now(),
+	}
+	if next.Hours == nil {
+		set["hours"] = []models.OpeningPeriod{}
+	}
+	if next.Coordinates != hub.Coordinates || next.LocationName != hub.LocationName {
+		located := hub
+		located.Coordinates, located.LocationName = next.Coordinates, next.LocationName
+		for k, v := range geocodeUpdate(located) {
+			set[k] = v
+		}
+	}
+	_, err := cfg.MongoClient.Database(cfg.DBName).Collection("hubs").UpdateOne(ctx,
+		bson.M{"_id": hub.ID},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return models.HubRevision{}, err
+	}
+	return recordHubRevision(ctx, cfg, hub.ID, hub.Fields(), next, author, source, editID, revertOf)
+}
+
+// recordHubRevision appends a revision for the change from before to after.
+// Hubs created before history existed first get a baseline revision so
+// their original values can be restored. Numbers are unique per hub; a
+// concurrent writer taking the same number makes us retry with the next.
+func recordHubRevision(ctx context.Context, cfg *config.Config, hubID primitive.ObjectID, before, after models.HubFields, author primitive.ObjectID, source string, editID, revertOf *primitive.ObjectID) (models.HubRevision, error) {
+	for attempt := 1; ; attempt++ {
+		revision, err := insertHubRevision(ctx, cfg, hubID, before, after, author, source, editID, revertOf)
+		if !mongo.IsDuplicateKeyError(err) || attempt == 5 {
+			return revision, err
+		}
+	}
+}
+
+func insertHubRevision(ctx context.Context, cfg *config.Config, hubID primitive.ObjectID, before, after models.HubFields, author primitive.ObjectID, source string, editID, revertOf *primitive.ObjectID) (models.HubRevision, error) {
+	col := cfg.MongoClient.Database(cfg.DBName).Collection("hub_revisions")
+
+	number := 1
+	var last models.HubRevision
+	err := col.FindOne(ctx, bson.M{"hub_id": hubID}, options.FindOne().SetSort(bson.M{"number": -1})).Decode(&last)
+	if err == nil {
+		number = last.Number + 1
+	} else if source != models.RevisionSourceCreate {
+		baseline := models.HubRevision{
+			ID:        primitive.NewObjectID(),
+			HubID:     hubID,
+			Number:    1,
+			Source:    models.RevisionSourceBaseline,
+			Changes:   models.HubFields{}.Diff(before),
+			Snapshot:  before,
+			CreatedAt: time.Now(),
+		}
+		if _, err := col.InsertOne(ctx, baseline); err != nil {
+			return models.HubRevision{}, err
+		}
+		number = 2
+	}
+
+	revision := models.HubRevision{
+		ID:        primitive.NewObjectID(),
+		HubID:     hubID,
+		Number:    number,
+		AuthorID:  author,
+		Source:    source,
+		EditID:    editID,
+		RevertOf:  revertOf,
+		Changes:   before.Diff(after),
+		Snapshot:  after,
+		CreatedAt: time.Now(),
+	}
+	if _, err := col.InsertOne(ctx, revision); err != nil {
+		return models.HubRevision{}, err
+	}
+	return revision, nil
+}
+
+// userNames returns a cached user-name lookup for enriching responses
+func userNames(ctx context.Context, cfg *config.Config) func(primitive.ObjectID) string {
+	userCol := cfg.MongoClient.Database(cfg.DBName).Collection("users")
+	cache := map[primitive.ObjectID]string{}
+	return func(id primitive.ObjectID) string {
+		if name, ok := cache[id]; ok {
+			return name
+		}
+		name := "Unknown User"
+		var user models.User
+		if !id.IsZero() {
+			if err := userCol.FindOne(ctx, bson.M{"_id": id}).Decode(&user); err == nil {
+				name = user.Name
+			}
+		}
+		cache[id] = name
+		return name
+	}
+}
@@ -0,0 +1,381 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+const (
+	// importInlineRows is the largest file imported within the request;
+	// bigger ones run in the background
+	importInlineRows = 200
+	// importProgressEvery is how many rows are saved to the job at a time
+	importProgressEvery = 50
+)
+
+// importedHub is a row accepted earlier in the same file, for catching
+// duplicates within the file
+type importedHub struct {
+	row    int
+	title  string
+	coords models.Coordinates
+}
+
+// ---------------- IMPORT ----------------
+// ImportHubs creates hubs from a CSV or GeoJSON "file" (admin only). Every
+// row is validated and checked for duplicates; rows that fail or look like
+// an existing hub are reported and left out. With dry_run=true nothing is
+// written. Files over importInlineRows rows are processed in the background:
+// the response is 202 with the job, to be polled at GET /hubs/import/:id.
+func ImportHubs(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only admins can import hubs"})
+			return
+		}
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		var input struct {
+			Format string `form:"format"` // csv or geojson; defaults to the file extension
+			DryRun bool   `form:"dry_run"`
+		}
+		if err := c.ShouldBind(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		file, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+			return
+		}
+		format, err := utils.ImportFormat(strings.ToLower(input.Format), file.Filename)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "could not read file"})
+			return
+		}
+		rows, err := utils.ParseHubImport(format, f)
+		f.Close()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		job := models.HubImport{
+			ID:        primitive.NewObjectID(),
+			CreatedBy: userID,
+			Filename:  file.Filename,
+			Format:    format,
+			DryRun:    input.DryRun,
+			Status:    models.ImportStatusQueued,
+			Total:     len(rows),
+			Rows:      []models.ImportRowResult{},
+			CreatedAt: time.Now(),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		col := cfg.MongoClient.Database(cfg.DBName).Collection("hub_imports")
+		if _, err := col.InsertOne(ctx, job); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not start import"})
+			return
+		}
+
+		if len(rows) > importInlineRows {
+			go runHubImport(cfg, job, rows)
+			c.Header("Location", "/hubs/import/"+job.ID.Hex())
+			c.JSON(http.StatusAccepted, job)
+			return
+		}
+
+		runHubImport(cfg, job, rows)
+
+		// the import can outlast ctx; read the report back with a fresh one
+		readCtx, readCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer readCancel()
+		if err := col.FindOne(readCtx, bson.M{"_id": job.ID}).Decode(&job); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load import report"})
+			return
+		}
+		c.JSON(http.StatusOK, job)
+	}
+}
+
+// ListHubImports lists recent imports without their row reports (admin only)
+func ListHubImports(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only admins can view imports"})
+			return
+		}
+		page, limit := pagination(c, 20, 100)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		col := cfg.MongoClient.Database(cfg.DBName).Collection("hub_imports")
+		total, err := col.CountDocuments(ctx, bson.M{})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not count imports"})
+			return
+		}
+		cursor, err := col.Find(ctx, bson.M{}, options.Find().
+			SetProjection(bson.M{"rows": 0}).
+			SetSort(bson.M{"created_at": -1}).
+			SetSkip(int64((page-1)*limit)).
+			SetLimit(int64(limit)))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch imports"})
+			return
+		}
+		imports := []models.HubImport{}
+		if err := cursor.All(ctx, &imports); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not decode imports"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"imports": imports,
+			"page":    page,
+			"limit":   limit,
+			"total":   total,
+		})
+	}
+}
+
+// GetHubImport returns an import's progress and row report (admin only).
+// ?status=created|valid|skipped|failed narrows the rows.
+func GetHubImport(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only admins can view imports"})
+			return
+		}
+		id, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid import id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var job models.HubImport
+		err = cfg.MongoClient.Database(cfg.DBName).Collection("hub_imports").FindOne(ctx, bson.M{"_id": id}).Decode(&job)
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "import not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch import"})
+			return
+		}
+
+		if status := c.Query("status"); status != "" {
+			rows := []models.ImportRowResult{}
+			for _, r := range job.Rows {
+				if r.Status == status {
+					rows = append(rows, r)
+				}
+			}
+			job.Rows = rows
+		}
+		c.JSON(http.StatusOK, job)
+	}
+}
+
+// =============================
+// Helpers
+// =============================
+
+// runHubImport processes every row, saving progress to the job as it goes
+func runHubImport(cfg *config.Config, job models.HubImport, rows []utils.HubImportRow) {
+	col := cfg.MongoClient.Database(cfg.DBName).Collection("hub_imports")
+	update := func(u bson.M) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := col.UpdateByID(ctx, job.ID, u); err != nil {
+			log.Printf("⚠️ Could not save progress of hub import %s: %v", job.ID.Hex(), err)
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("❌ Hub import %s crashed: %v", job.ID.Hex(), r)
+			update(bson.M{"$set": bson.M{
+				"status":      models.ImportStatusFailed,
+				"error":       "the import stopped unexpectedly",
+				"finished_at": time.Now(),
+			}})
+		}
+	}()
+
+	update(bson.M{"$set": bson.M{"status": models.ImportStatusRunning, "started_at": time.Now()}})
+
+	var (
+		accepted []importedHub
+		batch    []models.ImportRowResult
+		counts   = map[string]int{}
+	)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		update(bson.M{
+			"$push": bson.M{"rows": bson.M{"$each": batch}},
+			"$inc": bson.M{
+				"processed": len(batch),
+				"created":   counts[models.ImportRowCreated] + counts[models.ImportRowValid],
+				"skipped":   counts[models.ImportRowSkipped],
+				"failed":    counts[models.ImportRowFailed],
+			},
+		})
+		batch, counts = nil, map[string]int{}
+	}
+
+	for _, row := range rows {
+		res := importHubRow(cfg, job, row, &accepted)
+		batch = append(batch, res)
+		counts[res.Status]++
+		if len(batch) >= importProgressEvery {
+			flush()
+		}
+	}
+	flush()
+
+	update(bson.M{"$set": bson.M{"status": models.ImportStatusCompleted, "finished_at": time.Now()}})
+	log.Printf("✅ Hub import %s finished (%d rows, dry run: %t)", job.ID.Hex(), len(rows), job.DryRun)
+}
+
+// importHubRow validates one row and, unless it fails, duplicates a hub or
+// the job is a dry run, creates it
+func importHubRow(cfg *config.Config, job models.HubImport, row utils.HubImportRow, accepted *[]importedHub) models.ImportRowResult {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res := models.ImportRowResult{Row: row.Row, Title: strings.TrimSpace(row.Title)}
+	fail := func(errs ...string) models.ImportRowResult {
+		res.Status = models.ImportRowFailed
+		res.Errors = errs
+		return res
+	}
+
+	// --- Validate ---
+	errs := append([]string{}, row.Errors...)
+	if res.Title == "" {
+		errs = append(errs, "title is required")
+	}
+	var coords models.Coordinates
+	switch {
+	case row.Lat == nil || row.Lng == nil:
+		errs = append(errs, "lat and lng are required")
+	default:
+		coords = models.Coordinates{Lat: *row.Lat, Lng: *row.Lng}
+		if err := utils.ValidateCoordinates(coords); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	amenities, err := parseAmenities(row.Amenities)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	if row.PriceBand != "" && !models.IsValidPriceBand(row.PriceBand) {
+		errs = append(errs, "price_band must be one of free, low, medium, high")
+	}
+	hours, err := models.ParseOpeningHours(row.OpeningHours)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	categories, neighbourhood, tags, err := hubTerms(ctx, cfg, row.Categories, row.Neighbourhood, row.Tags)
+	if err != nil {
+		var unknown errUnknownTerm
+		if !errors.As(err, &unknown) && !errors.Is(err, errTooManyTags) {
+			return fail("could not check categories")
+		}
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		return fail(errs...)
+	}
+
+	// --- Duplicates of existing hubs, then of earlier rows ---
+	candidates, err := utils.FindDuplicateHubs(ctx, cfg, res.Title, coords, primitive.NilObjectID)
+	if err != nil {
+		return fail("could not check for duplicates")
+	}
+	if len(candidates) > 0 {
+		res.Status = models.ImportRowSkipped
+		for _, d := range candidates {
+			res.DuplicateOf = append(res.DuplicateOf, d.ID)
+		}
+		return res
+	}
+	for _, prev := range *accepted {
+		if utils.HaversineMeters(prev.coords, coords) <= utils.DuplicateRadiusMeters &&
+			utils.TitleSimilarity(prev.title, res.Title) >= utils.DuplicateMinSimilarity {
+			res.Status = models.ImportRowSkipped
+			res.DuplicateRow = prev.row
+			return res
+		}
+	}
+	*accepted = append(*accepted, importedHub{row: row.Row, title: res.Title, coords: coords})
+
+	if job.DryRun {
+		res.Status = models.ImportRowValid
+		return res
+	}
+
+	// --- Create ---
+	now := time.Now()
+	hub := models.Hub{
+		ID:            primitive.NewObjectID(),
+		UserID:        job.CreatedBy,
+		Title:         res.Title,
+		Description:   row.Description,
+		Coordinates:   coords,
+		LocationName:  row.LocationName,
+		Neighbourhood: neighbourhood,
+		Categories:    categories,
+		Tags:          tags,
+		Amenities:     amenities,
+		PriceBand:     row.PriceBand,
+		Hours:         hours,
+		Images:        []models.Asset{},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	utils.GeocodeHub(&hub)
+	if _, err := cfg.MongoClient.Database(cfg.DBName).Collection("hubs").InsertOne(ctx, hub); err != nil {
+		log.Printf("⚠️ Hub import %s: could not create row %d: %v", job.ID.Hex(), row.Row, err)
+		return fail("could not create hub")
+	}
+	if _, err := recordHubRevision(ctx, cfg, hub.ID, models.HubFields{}, hub.Fields(), job.CreatedBy, models.RevisionSourceImport, nil, nil); err != nil {
+		log.Printf("⚠️ could not record history of hub %s: %v", hub.ID.Hex(), err)
+	}
+	res.Status = models.ImportRowCreated
+	res.HubID = &hub.ID
+	return res
+}
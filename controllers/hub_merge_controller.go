@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+// ---------------- DUPLICATES ----------------
+// ListHubDuplicates returns likely duplicates of an existing hub
+func ListHubDuplicates(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var hub models.Hub
+		if err := cfg.MongoClient.Database(cfg.DBName).Collection("hubs").FindOne(ctx, bson.M{"_id": hubID}).Decode(&hub); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+
+		candidates, err := utils.FindDuplicateHubs(ctx, cfg, hub.Title, hub.Coordinates, hub.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not look for duplicates"})
+			return
+		}
+		if candidates == nil {
+			candidates = []utils.DuplicateCandidate{}
+		}
+
+		c.JSON(http.StatusOK, candidates)
+	}
+}
+
+// ---------------- MERGE ----------------
+// MergeHub (admin only) folds hub :id into the hub given as "into". Reviews,
+// favorites, collection entries, photos, check-ins and speed tests move to the survivor; the
+// merged hub is kept as a tombstone that GetHub redirects from.
+func MergeHub(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only admins can merge hubs"})
+			return
+		}
+
+		sourceID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		var input struct {
+			Into string `json:"into" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		targetID, err := primitive.ObjectIDFromHex(input.Into)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid target hub id"})
+			return
+		}
+		if targetID == sourceID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cannot merge a hub into itself"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		db := cfg.MongoClient.Database(cfg.DBName)
+		hubCol := db.Collection("hubs")
+
+		var source, target models.Hub
+		if err := hubCol.FindOne(ctx, bson.M{"_id": sourceID}).Decode(&source); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+		if err := hubCol.FindOne(ctx, bson.M{"_id": targetID}).Decode(&target); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "target hub not found"})
+			return
+		}
+		if source.MergedInto != nil || target.MergedInto != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "hub was already merged"})
+			return
+		}
+
+		moved := gin.H{}
+		fail := func(what string, err error) {
+			log.Printf("❌ merging hub %s into %s: %s: %v", sourceID.Hex(), targetID.Hex(), what, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not move " + what, "moved": moved})
+		}
+
+		// --- Reviews ---
+		res, err := db.Collection("reviews").UpdateMany(ctx, bson.M{"hub_id": sourceID}, bson.M{"$set": bson.M{"hub_id": targetID}})
+		if err != nil {
+			fail("reviews", err)
+			return
+		}
+		moved["reviews"] = res.ModifiedCount
+
+		// --- Favorites (users who favorited both keep one) ---
+		favCol := db.Collection("favorites")
+		both, err := favCol.Distinct(ctx, "user_id", bson.M{"hub_id": targetID})
+		if err != nil {
+			fail("favorites", err)
+			return
+		}
+		if _, err := favCol.DeleteMany(ctx, bson.M{"hub_id": sourceID, "user_id": bson.M{"$in": both}}); err != nil {
+			fail("favorites", err)
+			return
+		}
+		res, err = favCol.UpdateMany(ctx, bson.M{"hub_id": sourceID}, bson.M{"$set": bson.M{"hub_id": targetID}})
+		if err != nil {
+			fail("favorites", err)
+			return
+		}
+		moved["favorites"] = res.ModifiedCount
+
+		// --- Collections (lists that had both keep the survivor's entry) ---
+		collCol := db.Collection("collections")
+		if _, err := collCol.UpdateMany(ctx,
+			bson.M{"items.hub_id": bson.M{"$all": bson.A{sourceID, targetID}}},
+			bson.M{"$pull": bson.M{"items": bson.M{"hub_id": sourceID}}},
+		); err != nil {
+			fail("collections", err)
+			return
+		}
+		res, err = collCol.UpdateMany(ctx,
+			bson.M{"items.hub_id": sourceID},
+			bson.M{"$set": bson.M{"items.$[item].hub_id": targetID}},
+			options.Update().SetArrayFilters(options.ArrayFilters{Filters: bson.A{bson.M{"item.hub_id": sourceID}}}),
+		)
+		if err != nil {
+			fail("collections", err)
+			return
+		}
+		moved["collections"] = res.ModifiedCount
+
+		// --- Photos, appended after the survivor's own ---
+		next, err := nextPhotoPosition(ctx, cfg, targetID)
+		if err != nil {
+			fail("photos", err)
+			return
+		}
+		res, err = db.Collection("hub_photos").UpdateMany(ctx,
+			bson.M{"hub_id": sourceID},
+			bson.M{"$set": bson.M{"hub_id": targetID}, "$inc": bson.M{"position": next}},
+		)
+		if err != nil {
+			fail("photos", err)
+			return
+		}
+		moved["photos"] = res.ModifiedCount
+
+		// --- Check-ins ---
+		res, err = db.Collection("checkins").UpdateMany(ctx, bson.M{"hub_id": sourceID}, bson.M{"$set": bson.M{"hub_id": targetID}})
+		if err != nil {
+			fail("check-ins", err)
+			return
+		}
+		moved["checkins"] = res.ModifiedCount
+
+		// --- Wi-Fi speed tests ---
+		res, err = db.Collection("wifi_tests").UpdateMany(ctx, bson.M{"hub_id": sourceID}, bson.M{"$set": bson.M{"hub_id": targetID}})
+		if err != nil {
+			fail("wifi tests", err)
+			return
+		}
+		moved["wifi_tests"] = res.ModifiedCount
+
+		// --- Tombstone, and point older redirects straight at the survivor ---
+		now := time.Now()
+		_, err = hubCol.UpdateOne(ctx, bson.M{"_id": sourceID}, bson.M{
+			"$set":   bson.M{"merged_into": targetID, "merged_at": now, "images": []models.Asset{}, "updated_at": now},
+			"$unset": bson.M{"cover_photo_id": "", "wifi": ""},
+		})
+		if err != nil {
+			fail("hub", err)
+			return
+		}
+		if _, err := hubCol.UpdateMany(ctx, bson.M{"merged_into": sourceID}, bson.M{"$set": bson.M{"merged_into": targetID}}); err != nil {
+			log.Printf("⚠️ could not repoint redirects to hub %s: %v", sourceID.Hex(), err)
+		}
+
+		if err := rebuildHubImages(ctx, cfg, targetID); err != nil {
+			log.Printf("⚠️ could not refresh images of hub %s: %v", targetID.Hex(), err)
+		}
+		if _, err := utils.RefreshHubWiFi(ctx, cfg, targetID); err != nil {
+			log.Printf("⚠️ could not refresh wifi stats of hub %s: %v", targetID.Hex(), err)
+		}
+		if err := utils.RecountFavorites(ctx, cfg, sourceID, targetID); err != nil {
+			log.Printf("⚠️ could not recount favorites of hub %s: %v", targetID.Hex(), err)
+		}
+
+		if source.UserID != target.UserID {
+			go utils.CreateNotification(cfg, []primitive.ObjectID{source.UserID},
+				"Hub merged",
+				fmt.Sprintf("%s was a duplicate of %s and has been merged into it.", source.Title, target.Title))
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":     "Hub merged successfully",
+			"id":          sourceID.Hex(),
+			"merged_into": targetID.Hex(),
+			"moved":       moved,
+		})
+	}
+}
@@ -0,0 +1,648 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+// ---------------- LIST PHOTOS ----------------
+// ListHubPhotos returns the approved gallery, or only the verified owner's
+// official photos with ?official=true. Owners and moderators can pass
+// ?status=pending to see the moderation queue; rejected photos are deleted.
+func ListHubPhotos(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var hub models.Hub
+		if err := cfg.MongoClient.Database(cfg.DBName).Collection("hubs").FindOne(ctx, bson.M{"_id": hubID}).Decode(&hub); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+
+		status := c.DefaultQuery("status", models.PhotoStatusApproved)
+		if status != models.PhotoStatusApproved && !canManageHub(c, hub) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		filter := bson.M{"hub_id": hubID, "status": status}
+		if c.Query("official") == "true" {
+			filter["official"] = true
+		}
+		if !isModerator(c) {
+			filter["hidden"] = bson.M{"$ne": true}
+		}
+		photos, err := findHubPhotos(ctx, cfg, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch photos"})
+			return
+		}
+		if status == models.PhotoStatusApproved {
+			photos = galleryOrder(photos, hub.CoverPhotoID)
+		}
+
+		c.JSON(http.StatusOK, photos)
+	}
+}
+
+// ---------------- ADD PHOTOS ----------------
+// AddHubPhotos uploads one or more files under "photos". Photos from the
+// owner or a moderator are published straight away, anyone else's wait
+// in the moderation queue. A verified owner's photos are marked official.
+func AddHubPhotos(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		var input struct {
+			Caption  string `form:"caption" binding:"max=280"`
+			Category string `form:"category"`
+		}
+		if err := c.ShouldBind(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !models.IsValidPhotoCategory(input.Category) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "category must be one of interior, outlets, menu, exterior"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var hub models.Hub
+		if err := cfg.MongoClient.Database(cfg.DBName).Collection("hubs").FindOne(ctx, bson.M{"_id": hubID}).Decode(&hub); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+
+		status := models.PhotoStatusPending
+		if canManageHub(c, hub) {
+			status = models.PhotoStatusApproved
+		}
+
+		// The gallery quota counts approved photos only; everyone else's
+		// queued uploads are capped per uploader so they can't fill it
+		photoCol := cfg.MongoClient.Database(cfg.DBName).Collection("hub_photos")
+		quota := bson.M{"hub_id": hubID, "status": models.PhotoStatusApproved}
+		maxFiles := cfg.Uploads.MaxHubImages
+		if status == models.PhotoStatusPending {
+			quota = bson.M{"hub_id": hubID, "status": models.PhotoStatusPending, "uploaded_by": userID}
+			maxFiles = cfg.Uploads.MaxPendingPhotos
+		}
+		existing, err := photoCol.CountDocuments(ctx, quota)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not count photos"})
+			return
+		}
+
+		uploads, ok := imageUploads(c, "photos", utils.ImageLimits{
+			MaxFileBytes: cfg.Uploads.MaxFileBytes,
+			MaxFiles:     maxFiles,
+			Existing:     int(existing),
+		})
+		if !ok {
+			return
+		}
+		if len(uploads) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "no photos uploaded"})
+			return
+		}
+
+		assets, results, err := utils.UploadImages(cfg, "hubs", uploads, userID)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "image upload failed", "uploads": results})
+			return
+		}
+
+		next, err := nextPhotoPosition(ctx, cfg, hubID)
+		if err != nil {
+			utils.RollbackUploads(cfg, assets)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not add photos"})
+			return
+		}
+
+		now := time.Now()
+		photos := make([]models.HubPhoto, 0, len(assets))
+		docs := make([]interface{}, 0, len(assets))
+		for i, asset := range assets {
+			p := models.HubPhoto{
+				ID:         primitive.NewObjectID(),
+				HubID:      hubID,
+				Asset:      asset,
+				Caption:    input.Caption,
+				Category:   input.Category,
+				UploadedBy: userID,
+				Status:     status,
+				Official:   isVerifiedOwner(c, hub),
+				Position:   next + i,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			}
+			photos = append(photos, p)
+			docs = append(docs, p)
+		}
+
+		writeCtx, writeCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer writeCancel()
+
+		if _, err := photoCol.InsertMany(writeCtx, docs); err != nil {
+			utils.RollbackUploads(cfg, assets)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not add photos"})
+			return
+		}
+
+		if status == models.PhotoStatusApproved {
+			if err := rebuildHubImages(writeCtx, cfg, hubID); err != nil {
+				log.Printf("⚠️ could not refresh images of hub %s: %v", hubID.Hex(), err)
+			}
+		} else {
+			go utils.CreateNotification(cfg, []primitive.ObjectID{hub.UserID},
+				"New photos to review",
+				fmt.Sprintf("%d new photo(s) were submitted for %s and are waiting for your approval.", len(photos), hub.Title))
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"photos":  photos,
+			"status":  status,
+			"uploads": results,
+		})
+	}
+}
+
+// ---------------- UPDATE PHOTO ----------------
+// UpdateHubPhoto edits caption/category (uploader, owner or moderator),
+// approves or rejects queued photos (owner or moderator) and marks photos
+// official (verified owner). A rejected photo is deleted along with its
+// file; the response still reports it as rejected.
+func UpdateHubPhoto(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hub, photo, ok := loadHubPhoto(c, cfg)
+		if !ok {
+			return
+		}
+
+		var input struct {
+			Caption  *string `json:"caption" binding:"omitempty,max=280"`
+			Category *string `json:"category"`
+			Status   string  `json:"status" binding:"omitempty,oneof=approved rejected"`
+			Official *bool   `json:"official"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		manager := canManageHub(c, hub)
+		if !manager && photo.UploadedBy.Hex() != c.GetString("user_id") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		now := time.Now()
+		update := bson.M{"updated_at": now}
+		if input.Caption != nil {
+			update["caption"] = *input.Caption
+		}
+		if input.Category != nil {
+			if !models.IsValidPhotoCategory(*input.Category) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "category must be one of interior, outlets, menu, exterior"})
+				return
+			}
+			update["category"] = *input.Category
+		}
+		if input.Official != nil && *input.Official != photo.Official {
+			// only the verified owner vouches for a photo; moderators may withdraw it
+			if !isVerifiedOwner(c, hub) && (*input.Official || !isModerator(c)) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "only the hub's verified owner can mark photos official"})
+				return
+			}
+			update["official"] = *input.Official
+		}
+		if input.Status != "" && input.Status != photo.Status {
+			if !manager {
+				c.JSON(http.StatusForbidden, gin.H{"error": "only the hub owner or a moderator can moderate photos"})
+				return
+			}
+			reviewer, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+			update["status"] = input.Status
+			update["reviewed_by"] = reviewer
+			update["reviewed_at"] = now
+			if input.Status == models.PhotoStatusApproved {
+				approved, err := cfg.MongoClient.Database(cfg.DBName).Collection("hub_photos").CountDocuments(c.Request.Context(),
+					bson.M{"hub_id": hub.ID, "status": models.PhotoStatusApproved})
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "could not count photos"})
+					return
+				}
+				if cfg.Uploads.MaxHubImages > 0 && int(approved) >= cfg.Uploads.MaxHubImages {
+					c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("hub already has %d approved photos", approved)})
+					return
+				}
+				next, err := nextPhotoPosition(c.Request.Context(), cfg, hub.ID)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "could not place photo"})
+					return
+				}
+				update["position"] = next
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		col := cfg.MongoClient.Database(cfg.DBName).Collection("hub_photos")
+		var updated models.HubPhoto
+		err := col.FindOneAndUpdate(ctx,
+			bson.M{"_id": photo.ID},
+			bson.M{"$set": update},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&updated)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update photo"})
+			return
+		}
+
+		if photo.Status == models.PhotoStatusApproved || updated.Status == models.PhotoStatusApproved {
+			if err := rebuildHubImages(ctx, cfg, hub.ID); err != nil {
+				log.Printf("⚠️ could not refresh images of hub %s: %v", hub.ID.Hex(), err)
+			}
+		}
+		if updated.Status == models.PhotoStatusRejected {
+			// anything that fails stays recorded and is retried by the asset reconciler
+			if _, err := col.DeleteOne(ctx, bson.M{"_id": updated.ID}); err != nil {
+				log.Printf("⚠️ could not delete rejected photo %s: %v", updated.ID.Hex(), err)
+			} else if err := utils.DeleteAsset(cfg, updated.Asset); err != nil {
+				log.Printf("⚠️ could not delete image %s: %v", updated.Asset.URL, err)
+			}
+		}
+		if updated.Status != photo.Status && updated.UploadedBy != hub.UserID {
+			go utils.CreateNotification(cfg, []primitive.ObjectID{updated.UploadedBy},
+				"Your photo was "+updated.Status,
+				fmt.Sprintf("Your photo of %s was %s.", hub.Title, updated.Status))
+		}
+
+		c.JSON(http.StatusOK, updated)
+	}
+}
+
+// ---------------- DELETE PHOTO ----------------
+func DeleteHubPhoto(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hub, photo, ok := loadHubPhoto(c, cfg)
+		if !ok {
+			return
+		}
+
+		if !canManageHub(c, hub) && photo.UploadedBy.Hex() != c.GetString("user_id") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := cfg.MongoClient.Database(cfg.DBName).Collection("hub_photos").DeleteOne(ctx, bson.M{"_id": photo.ID}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete photo"})
+			return
+		}
+		if err := rebuildHubImages(ctx, cfg, hub.ID); err != nil {
+			log.Printf("⚠️ could not refresh images of hub %s: %v", hub.ID.Hex(), err)
+		}
+
+		// anything that fails stays recorded and is retried by the asset reconciler
+		if err := utils.DeleteAsset(cfg, photo.Asset); err != nil {
+			log.Printf("⚠️ could not delete image %s: %v", photo.Asset.URL, err)
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "photo deleted",
+			"id":      photo.ID.Hex(),
+		})
+	}
+}
+
+// ---------------- REORDER PHOTOS ----------------
+// ReorderHubPhotos sets gallery order and, optionally, the cover photo.
+// Approved photos missing from photo_ids keep their relative order after
+// the listed ones.
+func ReorderHubPhotos(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		var input struct {
+			PhotoIDs     []string `json:"photo_ids"`
+			CoverPhotoID *string  `json:"cover_photo_id"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		hubCol := cfg.MongoClient.Database(cfg.DBName).Collection("hubs")
+		var hub models.Hub
+		if err := hubCol.FindOne(ctx, bson.M{"_id": hubID}).Decode(&hub); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+		if !canManageHub(c, hub) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		photos, err := findHubPhotos(ctx, cfg, galleryFilter(hubID))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch photos"})
+			return
+		}
+		byID := map[string]models.HubPhoto{}
+		for _, p := range photos {
+			byID[p.ID.Hex()] = p
+		}
+
+		// listed photos first, in the given order
+		ordered := []models.HubPhoto{}
+		seen := map[string]bool{}
+		for _, id := range input.PhotoIDs {
+			p, ok := byID[id]
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unknown or unapproved photo id", "photo_id": id})
+				return
+			}
+			if !seen[id] {
+				seen[id] = true
+				ordered = append(ordered, p)
+			}
+		}
+		for _, p := range photos {
+			if !seen[p.ID.Hex()] {
+				ordered = append(ordered, p)
+			}
+		}
+
+		photoCol := cfg.MongoClient.Database(cfg.DBName).Collection("hub_photos")
+		for i, p := range ordered {
+			if p.Position == i {
+				continue
+			}
+			if _, err := photoCol.UpdateOne(ctx, bson.M{"_id": p.ID}, bson.M{"$set": bson.M{"position": i}}); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not reorder photos"})
+				return
+			}
+		}
+
+		if input.CoverPhotoID != nil {
+			if *input.CoverPhotoID == "" {
+				_, err = hubCol.UpdateOne(ctx, bson.M{"_id": hubID}, bson.M{"$unset": bson.M{"cover_photo_id": ""}})
+			} else {
+				cover, ok := byID[*input.CoverPhotoID]
+				if !ok {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "cover must be an approved photo of this hub"})
+					return
+				}
+				_, err = hubCol.UpdateOne(ctx, bson.M{"_id": hubID}, bson.M{"$set": bson.M{"cover_photo_id": cover.ID}})
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not set cover photo"})
+				return
+			}
+		}
+
+		if err := rebuildHubImages(ctx, cfg, hubID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not refresh hub images"})
+			return
+		}
+
+		// Return the gallery as clients will now see it
+		if err := hubCol.FindOne(ctx, bson.M{"_id": hubID}).Decode(&hub); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve hub"})
+			return
+		}
+		photos, err = findHubPhotos(ctx, cfg, galleryFilter(hubID))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch photos"})
+			return
+		}
+
+		c.JSON(http.StatusOK, galleryOrder(photos, hub.CoverPhotoID))
+	}
+}
+
+// =============================
+// Helpers
+// =============================
+
+// loadHubPhoto resolves :id and :photoId, writing the error response itself
+func loadHubPhoto(c *gin.Context, cfg *config.Config) (models.Hub, models.HubPhoto, bool) {
+	var hub models.Hub
+	var photo models.HubPhoto
+
+	hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+		return hub, photo, false
+	}
+	photoID, err := primitive.ObjectIDFromHex(c.Param("photoId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid photo id"})
+		return hub, photo, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := cfg.MongoClient.Database(cfg.DBName).Collection("hubs").FindOne(ctx, bson.M{"_id": hubID}).Decode(&hub); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+		return hub, photo, false
+	}
+	if err := cfg.MongoClient.Database(cfg.DBName).Collection("hub_photos").
+		FindOne(ctx, bson.M{"_id": photoID, "hub_id": hubID}).Decode(&photo); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "photo not found"})
+		return hub, photo, false
+	}
+	return hub, photo, true
+}
+
+// findHubPhotos fetches photos by position with uploader names filled in
+func findHubPhotos(ctx context.Context, cfg *config.Config, filter bson.M) ([]models.HubPhoto, error) {
+	db := cfg.MongoClient.Database(cfg.DBName)
+	cursor, err := db.Collection("hub_photos").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	photos := []models.HubPhoto{}
+	if err := cursor.All(ctx, &photos); err != nil {
+		return nil, err
+	}
+
+	names := map[primitive.ObjectID]string{}
+	for i, p := range photos {
+		name, ok := names[p.UploadedBy]
+		if !ok {
+			var user models.User
+			if err := db.Collection("users").FindOne(ctx, bson.M{"_id": p.UploadedBy}).Decode(&user); err == nil {
+				name = user.Name
+			} else {
+				name = "Unknown User"
+			}
+			names[p.UploadedBy] = name
+		}
+		photos[i].UploaderName = name
+	}
+	return photos, nil
+}
+
+// galleryFilter matches the photos shown in a hub's public gallery
+func galleryFilter(hubID primitive.ObjectID) bson.M {
+	return bson.M{"hub_id": hubID, "status": models.PhotoStatusApproved, "hidden": bson.M{"$ne": true}}
+}
+
+// galleryOrder puts the cover photo first and flags it
+func galleryOrder(photos []models.HubPhoto, cover *primitive.ObjectID) []models.HubPhoto {
+	if cover == nil {
+		return photos
+	}
+	sort.SliceStable(photos, func(i, j int) bool {
+		return photos[i].ID == *cover && photos[j].ID != *cover
+	})
+	for i := range photos {
+		photos[i].IsCover = photos[i].ID == *cover
+	}
+	return photos
+}
+
+// nextPhotoPosition returns the position after the hub's last photo
+func nextPhotoPosition(ctx context.Context, cfg *config.Config, hubID primitive.ObjectID) (int, error) {
+	var last models.HubPhoto
+	err := cfg.MongoClient.Database(cfg.DBName).Collection("hub_photos").FindOne(ctx,
+		bson.M{"hub_id": hubID},
+		options.FindOne().SetSort(bson.D{{Key: "position", Value: -1}}),
+	).Decode(&last)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return last.Position + 1, nil
+}
+
+// rebuildHubImages mirrors visible approved photos into Hub.Images, cover
+// first, and drops a cover that is no longer shown.
+func rebuildHubImages(ctx context.Context, cfg *config.Config, hubID primitive.ObjectID) error {
+	hubCol := cfg.MongoClient.Database(cfg.DBName).Collection("hubs")
+
+	var hub models.Hub
+	if err := hubCol.FindOne(ctx, bson.M{"_id": hubID}).Decode(&hub); err != nil {
+		return err
+	}
+	photos, err := findHubPhotos(ctx, cfg, galleryFilter(hubID))
+	if err != nil {
+		return err
+	}
+
+	coverFound := false
+	images := make([]models.Asset, 0, len(photos))
+	for _, p := range galleryOrder(photos, hub.CoverPhotoID) {
+		images = append(images, p.Asset)
+		coverFound = coverFound || p.IsCover
+	}
+
+	update := bson.M{"$set": bson.M{"images": images, "updated_at": time.Now()}}
+	if hub.CoverPhotoID != nil && !coverFound {
+		update["$unset"] = bson.M{"cover_photo_id": ""}
+	}
+	_, err = hubCol.UpdateOne(ctx, bson.M{"_id": hubID}, update)
+	return err
+}
+
+// addGalleryPhotos records images uploaded through CreateHub/UpdateHub as
+// approved gallery photos
+func addGalleryPhotos(ctx context.Context, cfg *config.Config, hub models.Hub, assets []models.Asset) error {
+	if len(assets) == 0 {
+		return nil
+	}
+	next, err := nextPhotoPosition(ctx, cfg, hub.ID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, 0, len(assets))
+	for i, img := range assets {
+		uploader := img.UploadedBy
+		if uploader.IsZero() {
+			uploader = hub.UserID
+		}
+		docs = append(docs, models.HubPhoto{
+			ID:         primitive.NewObjectID(),
+			HubID:      hub.ID,
+			Asset:      img,
+			UploadedBy: uploader,
+			Status:     models.PhotoStatusApproved,
+			Position:   next + i,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		})
+	}
+	_, err = cfg.MongoClient.Database(cfg.DBName).Collection("hub_photos").InsertMany(ctx, docs)
+	return err
+}
+
+// removeGalleryPhotos drops the approved photos whose images were removed
+// through UpdateHub
+func removeGalleryPhotos(ctx context.Context, cfg *config.Config, hubID primitive.ObjectID, assets []models.Asset) error {
+	ids := make([]primitive.ObjectID, 0, len(assets))
+	for _, img := range assets {
+		if !img.ID.IsZero() {
+			ids = append(ids, img.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := cfg.MongoClient.Database(cfg.DBName).Collection("hub_photos").DeleteMany(ctx, bson.M{
+		"hub_id":    hubID,
+		"asset._id": bson.M{"$in": ids},
+		"status":    models.PhotoStatusApproved,
+	})
+	return err
+}
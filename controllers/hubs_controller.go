@@ -2,12 +2,20 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	config "github.com/phillip/contribution-tracker-go/config"
 	models "github.com/phillip/contribution-tracker-go/models"
@@ -29,48 +37,87 @@ func CreateHub(cfg *config.Config) gin.HandlerFunc {
 		var input struct {
 			Title        string   `form:"title" binding:"required"`
 			Description  string   `form:"description"`
-			Lat          float64  `form:"lat"`
-			Lng          float64  `form:"lng"`
+			Lat          *float64 `form:"lat"`
+			Lng          *float64 `form:"lng"`
 			LocationName string   `form:"location_name"`
+			Neighbourhood string  `form:"neighbourhood"` // taxonomy slug
+			Categories   []string `form:"categories"`    // taxonomy slugs, repeated or comma-separated
+			Tags         []string `form:"tags"`          // free-form, repeated or comma-separated
+			Amenities    []string `form:"amenities"` // repeated or comma-separated
+			PriceBand    string   `form:"price_band"`
 			Rating       float64  `form:"rating"`
+			ConfirmDuplicate bool `form:"confirm_duplicate"` // create even if similar hubs exist
 		}
 
 		if err := c.ShouldBind(&input); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		if input.Lat == nil || input.Lng == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "lat and lng are required"})
+			return
+		}
+		coords := models.Coordinates{Lat: *input.Lat, Lng: *input.Lng}
+		if err := utils.ValidateCoordinates(coords); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		amenities, err := parseAmenities(input.Amenities)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if input.PriceBand != "" && !models.IsValidPriceBand(input.PriceBand) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "price_band must be one of free, low, medium, high"})
+			return
+		}
 
-
-		// --- Handle file uploads ---
-		form, err := c.MultipartForm()
-		if err != nil && err != http.ErrNotMultipart {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid form data"})
+		// --- Categories, neighbourhood and tags ---
+		taxCtx, taxCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		categories, neighbourhood, tags, err := hubTerms(taxCtx, cfg, input.Categories, input.Neighbourhood, input.Tags)
+		taxCancel()
+		if err != nil {
+			var unknown errUnknownTerm
+			if errors.As(err, &unknown) || errors.Is(err, errTooManyTags) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not check categories"})
 			return
 		}
 
-		var imageURLs []string
-		if form != nil {
-			files := form.File["images"] // key must be "images"
-			for _, fileHeader := range files {
-				file, err := fileHeader.Open()
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open file"})
-					return
-				}
+		// --- Look for existing listings of the same place ---
+		if !input.ConfirmDuplicate {
+			dupCtx, dupCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			candidates, err := utils.FindDuplicateHubs(dupCtx, cfg, input.Title, coords, primitive.NilObjectID)
+			dupCancel()
+			if err != nil {
+				log.Printf("⚠️ duplicate check failed: %v", err)
+			} else if len(candidates) > 0 {
+				c.JSON(http.StatusConflict, gin.H{
+					"error":      "this place may already be listed; resend with confirm_duplicate=true to create it anyway",
+					"candidates": candidates,
+				})
+				return
+			}
+		}
 
-				url, err := utils.UploadToCloudinary(file, fileHeader)
-				file.Close()
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{
-						"error":   "image upload failed",
-						"details": err.Error(),
-						"file":    fileHeader.Filename,
-					})
-					return
-				}
+		// --- Handle file uploads ---
+		uploads, ok := imageUploads(c, "images", utils.ImageLimits{ // key must be "images"
+			MaxFileBytes: cfg.Uploads.MaxFileBytes,
+			MaxFiles:     cfg.Uploads.MaxHubImages,
+		})
+		if !ok {
+			return
+		}
 
-				imageURLs = append(imageURLs, url)
-			}
+		images, results, err := utils.UploadImages(cfg, "hubs", uploads, userID)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{
+				"error":   "image upload failed",
+				"uploads": results,
+			})
+			return
 		}
 
 		// --- Save hub ---
@@ -80,26 +127,37 @@ func CreateHub(cfg *config.Config) gin.HandlerFunc {
 			UserID:       userID,
 			Title:        input.Title,
 			Description:  input.Description,
-			Coordinates: models.Coordinates{
-				Lat: input.Lat,
-				Lng: input.Lng,
-			},
+			Coordinates:  coords,
 			LocationName: input.LocationName,
+			Neighbourhood: neighbourhood,
+			Categories:   categories,
+			Tags:         tags,
+			Amenities:    amenities,
+			PriceBand:    input.PriceBand,
 			Rating:       input.Rating,
-			Images:       imageURLs,
+			Images:       images,
 			CreatedAt:    now,
 			UpdatedAt:    now,
 		}
+		utils.GeocodeHub(&hub)
 
 		col := cfg.MongoClient.Database(cfg.DBName).Collection("hubs")
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
 		if _, err := col.InsertOne(ctx, hub); err != nil {
+			utils.RollbackUploads(cfg, images)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create hub"})
 			return
 		}
+		if err := addGalleryPhotos(ctx, cfg, hub, hub.Images); err != nil {
+			log.Printf("⚠️ could not record gallery of hub %s: %v", hub.ID.Hex(), err)
+		}
+		if _, err := recordHubRevision(ctx, cfg, hub.ID, models.HubFields{}, hub.Fields(), userID, models.RevisionSourceCreate, nil, nil); err != nil {
+			log.Printf("⚠️ could not record history of hub %s: %v", hub.ID.Hex(), err)
+		}
 
+		hub.Uploads = results
 		c.JSON(http.StatusCreated, hub)
 	}
 }
@@ -124,12 +182,30 @@ func ListHubs(cfg *config.Config) gin.HandlerFunc {
 		defer cancel()
 
 		// --- Build filter ---
-		filter := bson.M{}
-		if q := c.Query("q"); q != "" {
-			filter["title"] = bson.M{"$regex": q, "$options": "i"}
+		filter, err := hubListFilter(c, cfg)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
 
-		cursor, err := hubCol.Find(ctx, filter)
+		origin, err := queryOrigin(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// --- Text searches rank by relevance unless another sort is asked for ---
+		search := c.Query("q") != ""
+		byRelevance := search && (c.Query("sort") == "" || c.Query("sort") == "relevance")
+
+		opts := options.Find()
+		if search {
+			opts.SetProjection(bson.M{"text_score": bson.M{"$meta": "textScore"}})
+		}
+		if !byRelevance {
+			opts.SetSort(hubListSort(c))
+		}
+		cursor, err := hubCol.Find(ctx, filter, opts)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch hubs"})
 			return
@@ -141,10 +217,40 @@ func ListHubs(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
+		if byRelevance {
+			ratings, err := hubRatings(ctx, cfg, hubs)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load ratings"})
+				return
+			}
+			top := 0.0
+			for _, h := range hubs {
+				top = math.Max(top, h.TextScore)
+			}
+			for i, h := range hubs {
+				var dist *float64
+				if origin != nil && (h.Coordinates.Lat != 0 || h.Coordinates.Lng != 0) {
+					d := utils.HaversineMeters(*origin, h.Coordinates)
+					dist = &d
+				}
+				hubs[i].Relevance = utils.SearchRelevance(h.TextScore, top, ratings[h.ID].Avg, ratings[h.ID].Count, dist)
+			}
+			utils.SortByRelevance(hubs)
+		}
+
+		// --- Only hubs that are usually quiet at this hour ---
+		if c.Query("quiet_now") == "true" {
+			hubs, err = quietHubs(ctx, cfg, hubs)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load popular times"})
+				return
+			}
+		}
+
 		for i, hub := range hubs {
 			// --- Fetch Reviews for this Hub ---
 			var reviews []models.Review
-			reviewCursor, err := reviewCol.Find(ctx, bson.M{"hub_id": hub.ID})
+			reviewCursor, err := reviewCol.Find(ctx, visibleReviews(hub.ID))
 			if err == nil {
 				_ = reviewCursor.All(ctx, &reviews)
 			}
@@ -168,6 +274,7 @@ func ListHubs(cfg *config.Config) gin.HandlerFunc {
 					HubID:     r.HubID,
 					Rating:    r.Rating,
 					Comment:   r.Comment,
+					OwnerReply: r.OwnerReply,
 					CreatedAt: r.CreatedAt,
 				})
 			}
@@ -180,6 +287,20 @@ func ListHubs(cfg *config.Config) gin.HandlerFunc {
 			hubs[i].IsFavorite = (err == nil)
 		}
 
+		// --- Facet counts for the filter UI (wraps the list in an object) ---
+		if c.Query("facets") == "true" {
+			facets, err := hubFacets(ctx, cfg, filter)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not count facets"})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{
+				"hubs":   hubs,
+				"facets": facets,
+			})
+			return
+		}
+
 		c.JSON(http.StatusOK, hubs)
 	}
 }
@@ -220,11 +341,27 @@ func GetHub(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
+		// --- Hidden hubs are only visible to moderators ---
+		if hub.Hidden && !isModerator(c) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+
+		// --- Merged hubs redirect to the surviving one ---
+		if hub.MergedInto != nil {
+			c.Header("Location", "/hubs/"+hub.MergedInto.Hex())
+			c.JSON(http.StatusMovedPermanently, gin.H{
+				"error":       "hub was merged",
+				"merged_into": hub.MergedInto.Hex(),
+			})
+			return
+		}
+
 		// --- Fetch reviews for this hub ---
 		reviewColl := cfg.MongoClient.Database(cfg.DBName).Collection("reviews")
 		userColl := cfg.MongoClient.Database(cfg.DBName).Collection("users")
 
-		cursor, err := reviewColl.Find(ctx, bson.M{"hub_id": hubID})
+		cursor, err := reviewColl.Find(ctx, visibleReviews(hubID))
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch reviews"})
 			return
@@ -236,6 +373,7 @@ func GetHub(cfg *config.Config) gin.HandlerFunc {
 			UserName  string             `json:"user_name"`
 			Comment   string             `json:"comment"`
 			Rating    int                `json:"rating"`
+			OwnerReply *models.OwnerReply `json:"owner_reply,omitempty"`
 			CreatedAt time.Time          `json:"created_at"`
 		}
 
@@ -257,6 +395,7 @@ func GetHub(cfg *config.Config) gin.HandlerFunc {
 				UserName:  user.Name,
 				Comment:   review.Comment,
 				Rating:    review.Rating,
+				OwnerReply: review.OwnerReply,
 				CreatedAt: review.CreatedAt,
 			})
 		}
@@ -284,6 +423,7 @@ func GetHub(cfg *config.Config) gin.HandlerFunc {
 			"hub":        hub,
 			"reviews":    reviews,
 			"is_favorite": isFavorite,
+			"live":       liveStatus(ctx, cfg, hubID),
 		})
 	}
 }
@@ -331,8 +471,13 @@ func UpdateHub(cfg *config.Config) gin.HandlerFunc {
 			Lat          *float64  `form:"lat"`
 			Lng          *float64  `form:"lng"`
 			LocationName string   `form:"location_name"`
+			Neighbourhood string  `form:"neighbourhood"`
+			Categories   []string `form:"categories"` // replaces the list; send one empty value to clear
+			Tags         []string `form:"tags"`       // replaces the list; send one empty value to clear
+			Amenities    []string `form:"amenities"` // replaces the list; send one empty value to clear
+			PriceBand    string   `form:"price_band"`
 			Rating       float64  `form:"rating"`
-			Images       []string `form:"images"` 
+			Images       []string `form:"images"` // existing image IDs or URLs to keep
 		}
 
 
@@ -340,6 +485,10 @@ func UpdateHub(cfg *config.Config) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		if input.PriceBand != "" && !models.IsValidPriceBand(input.PriceBand) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "price_band must be one of free, low, medium, high"})
+			return
+		}
 
 		// ✅ Prepare update document
 		update := bson.M{"updated_at": time.Now()}
@@ -351,47 +500,101 @@ func UpdateHub(cfg *config.Config) gin.HandlerFunc {
 			update["description"] = input.Description
 		}
 		if input.LocationName != "" {
-			update["location_name"] = input.LocationName
+			update["location"] = input.LocationName
+		}
+		if input.Neighbourhood != "" || input.Categories != nil || input.Tags != nil {
+			categories, neighbourhood, tags, err := hubTerms(ctx, cfg, input.Categories, input.Neighbourhood, input.Tags)
+			if err != nil {
+				var unknown errUnknownTerm
+				if errors.As(err, &unknown) || errors.Is(err, errTooManyTags) {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not check categories"})
+				return
+			}
+			if input.Neighbourhood != "" {
+				update["neighbourhood"] = neighbourhood
+			}
+			if input.Categories != nil {
+				update["categories"] = categories
+			}
+			if input.Tags != nil {
+				update["tags"] = tags
+			}
+		}
+		if input.Amenities != nil {
+			amenities, err := parseAmenities(input.Amenities)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			update["amenities"] = amenities
+		}
+		if input.PriceBand != "" {
+			update["price_band"] = input.PriceBand
 		}
 		if input.Rating > 0 {
 			update["rating"] = input.Rating
 		}
-		// Coordinates (nested)
-		coordinatesUpdate := bson.M{}
-		if input.Lat != nil { // assuming you use *float64 for optional numbers
-			coordinatesUpdate["lat"] = input.Lat
+		// Coordinates; either may be sent alone
+		if input.Lat != nil || input.Lng != nil {
+			coords := existing.Coordinates
+			if input.Lat != nil {
+				coords.Lat = *input.Lat
+			}
+			if input.Lng != nil {
+				coords.Lng = *input.Lng
+			}
+			if err := utils.ValidateCoordinates(coords); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			update["coordinates"] = coords
 		}
-		if input.Lng != nil {
-			coordinatesUpdate["lng"] = input.Lng
+		// Re-derive the address when the place changes
+		if update["coordinates"] != nil || update["location"] != nil || update["neighbourhood"] != nil {
+			located := existing
+			if coords, ok := update["coordinates"].(models.Coordinates); ok {
+				located.Coordinates = coords
+			}
+			if name, ok := update["location"].(string); ok {
+				located.LocationName = name
+			}
+			if nb, ok := update["neighbourhood"].(string); ok {
+				located.Neighbourhood = nb
+			}
+			for k, v := range geocodeUpdate(located) {
+				update[k] = v
+			}
 		}
-		if len(coordinatesUpdate) > 0 {
-			update["coordinates"] = coordinatesUpdate
+
+		// ✅ Existing images to keep (all of them unless the client lists some)
+		kept := existing.Images
+		if input.Images != nil {
+			kept = keepImages(existing.Images, input.Images)
 		}
 
 		// ✅ Handle new image uploads (multipart form)
-		newImageURLs := []string{}
-		form, _ := c.MultipartForm()
-		if form != nil {
-			files := form.File["new_images"] // key = "new_images"
-			for _, fileHeader := range files {
-				file, err := fileHeader.Open()
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open image"})
-					return
-				}
-				url, err := utils.UploadToCloudinary(file, fileHeader)
-				file.Close()
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "image upload failed", "details": err.Error()})
-					return
-				}
-				newImageURLs = append(newImageURLs, url)
-			}
+		uploads, ok := imageUploads(c, "new_images", utils.ImageLimits{ // key = "new_images"
+			MaxFileBytes: cfg.Uploads.MaxFileBytes,
+			MaxFiles:     cfg.Uploads.MaxHubImages,
+			Existing:     len(kept),
+		})
+		if !ok {
+			return
 		}
 
-		// ✅ Merge images (keep provided + add new)
-		if input.Images != nil || len(newImageURLs) > 0 {
-			update["images"] = append(input.Images, newImageURLs...)
+		uploaderID, _ := primitive.ObjectIDFromHex(requesterID)
+		newImages, results, err := utils.UploadImages(cfg, "hubs", uploads, uploaderID)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "image upload failed", "uploads": results})
+			return
+		}
+
+		// ✅ Merge images (keep listed existing ones + add new)
+		if input.Images != nil || len(newImages) > 0 {
+			update["images"] = append(kept, newImages...)
 		}
 
 		// ❗ Reject empty update
@@ -400,23 +603,40 @@ func UpdateHub(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		// ✅ Apply update
-		_, err = col.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": update})
+		// ✅ Apply update (fresh timeout, uploads may have used up ctx)
+		writeCtx, writeCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer writeCancel()
+
+		_, err = col.UpdateOne(writeCtx, bson.M{"_id": objID}, bson.M{"$set": update})
 		if err != nil {
+			utils.RollbackUploads(cfg, newImages)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update hub"})
 			return
 		}
 
+		// ✅ Keep gallery records in step with the images just written
+		if update["images"] != nil {
+			if err := syncGalleryUpdate(writeCtx, cfg, existing, kept, newImages); err != nil {
+				log.Printf("⚠️ could not sync gallery of hub %s: %v", objID.Hex(), err)
+			}
+		}
+
 		// ✅ Fetch updated hub
 		var updated models.Hub
-		if err := col.FindOne(ctx, bson.M{"_id": objID}).Decode(&updated); err != nil {
+		if err := col.FindOne(writeCtx, bson.M{"_id": objID}).Decode(&updated); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve updated hub"})
 			return
 		}
+		if len(existing.Fields().Diff(updated.Fields())) > 0 {
+			if _, err := recordHubRevision(writeCtx, cfg, objID, existing.Fields(), updated.Fields(), uploaderID, models.RevisionSourceOwnerEdit, nil, nil); err != nil {
+				log.Printf("⚠️ could not record history of hub %s: %v", objID.Hex(), err)
+			}
+		}
 
 		c.JSON(http.StatusOK, gin.H{
 			"message": "Hub updated successfully",
 			"hub":   updated,
+			"uploads": results,
 		})
 	}
 }
@@ -458,21 +678,16 @@ func DeleteHub(cfg *config.Config) gin.HandlerFunc {
 		}
 
 		// ✅ Delete hub
-		res, err := col.DeleteOne(ctx, bson.M{"_id": oid})
+		deleted, err := removeHub(ctx, cfg, existing)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete hub"})
 			return
 		}
-		if res.DeletedCount == 0 {
+		if !deleted {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Hub not found"})
 			return
 		}
 
-		// 🔹 (Optional) TODO: Delete images from Cloudinary
-		for _, img := range existing.Images {
-			  utils.DeleteFromCloudinary(img)
-		}
-
 		c.JSON(http.StatusOK, gin.H{
 			"message": "Hub deleted successfully",
 			"id":      oid.Hex(),
@@ -510,6 +725,8 @@ func AddReview(cfg *config.Config) gin.HandlerFunc {
 			HubID:     hubID,
 			Rating:    input.Rating,
 			Comment:   input.Comment,
+			Status:    models.ReviewStatusPublished,
+			TextHash:  utils.ReviewTextHash(input.Comment),
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		}
@@ -518,16 +735,183 @@ func AddReview(cfg *config.Config) gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
+		// --- Screen before publishing ---
+		review.Flags = utils.ScreenReview(ctx, utils.ScreenInput{UserID: userID, HubID: hubID, Text: review.Comment})
+		if len(review.Flags) > 0 {
+			review.Status = models.ReviewStatusPending
+		}
+
 		if _, err := col.InsertOne(ctx, review); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not add review"})
 			return
 		}
+		if review.Status == models.ReviewStatusPending {
+			if err := fileScreeningReport(ctx, cfg, review); err != nil {
+				log.Printf("⚠️ could not queue review %s for moderation: %v", review.ID.Hex(), err)
+			}
+		}
 
 		c.JSON(http.StatusCreated, review)
 	}
 }
 
+// ---------------- UPDATE REVIEW ----------------
+// UpdateReview lets authors edit their review. The new text is screened
+// again; a review that is pending stays so until a moderator clears it.
+func UpdateReview(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+		reviewID, err := primitive.ObjectIDFromHex(c.Param("reviewId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid review id"})
+			return
+		}
+
+		var input struct {
+			Rating  *int    `json:"rating" binding:"omitempty,min=1,max=5"`
+			Comment *string `json:"comment"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		col := cfg.MongoClient.Database(cfg.DBName).Collection("reviews")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var review models.Review
+		if err := col.FindOne(ctx, bson.M{"_id": reviewID, "hub_id": hubID}).Decode(&review); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "review not found"})
+			return
+		}
+		if review.UserID != userID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		update := bson.M{"updated_at": time.Now()}
+		if input.Rating != nil {
+			review.Rating = *input.Rating
+			update["rating"] = review.Rating
+		}
+		if input.Comment != nil && *input.Comment != review.Comment {
+			review.Comment = *input.Comment
+			review.TextHash = utils.ReviewTextHash(review.Comment)
+			update["comment"] = review.Comment
+			update["text_hash"] = review.TextHash
+
+			flags := utils.ScreenReview(ctx, utils.ScreenInput{ReviewID: reviewID, UserID: userID, HubID: hubID, Text: review.Comment})
+			if len(flags) > 0 {
+				review.Status = models.ReviewStatusPending
+				review.Flags = flags
+				update["status"] = review.Status
+				update["flags"] = flags
+			}
+		}
+
+		if _, err := col.UpdateOne(ctx, bson.M{"_id": reviewID}, bson.M{"$set": update}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update review"})
+			return
+		}
+		if _, held := update["flags"]; held {
+			if err := fileScreeningReport(ctx, cfg, review); err != nil {
+				log.Printf("⚠️ could not queue review %s for moderation: %v", review.ID.Hex(), err)
+			}
+		}
+
+		c.JSON(http.StatusOK, review)
+	}
+}
+
+
+// ---------------- FAVORITES ----------------
+// AddFavorite favorites a hub for the caller. It is idempotent: repeating
+// it leaves a single favorite.
+func AddFavorite(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
 
+		err = cfg.MongoClient.Database(cfg.DBName).Collection("hubs").FindOne(ctx, bson.M{
+			"_id":         hubID,
+			"merged_into": bson.M{"$exists": false},
+			"hidden":      bson.M{"$ne": true},
+		}).Err()
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+
+		if _, err := addFavorite(ctx, cfg, userID, hubID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not mark as favorite"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"favorite":        true,
+			"favorites_count": favoritesCount(ctx, cfg, hubID),
+		})
+	}
+}
+
+// RemoveFavorite unfavorites a hub for the caller. Removing a hub that
+// isn't a favorite succeeds too.
+func RemoveFavorite(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := removeFavorite(ctx, cfg, userID, hubID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not remove favorite"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"favorite":        false,
+			"favorites_count": favoritesCount(ctx, cfg, hubID),
+		})
+	}
+}
+
+// ToggleFavorite flips the favorite state of a hub.
+//
+// Deprecated: clients should use PUT and DELETE /hubs/:id/favorite, which
+// are safe to retry.
 func ToggleFavorite(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userIDHex := c.GetString("user_id")
@@ -543,46 +927,48 @@ func ToggleFavorite(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		col := cfg.MongoClient.Database(cfg.DBName).Collection("favorites")
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		// ✅ Check if already favorited
-		var fav models.Favorite
-		err = col.FindOne(ctx, bson.M{"user_id": userID, "hub_id": hubID}).Decode(&fav)
-
-		if err == nil {
-			// ✅ Unfavorite
-			_, _ = col.DeleteOne(ctx, bson.M{"_id": fav.ID})
+		// ✅ Unfavorite if already favorited; unfavoriting is allowed for a
+		// hub that has since been hidden or merged
+		removed, err := removeFavorite(ctx, cfg, userID, hubID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not remove favorite"})
+			return
+		}
+		if removed {
 			c.JSON(http.StatusOK, gin.H{
 				"message":  "removed from favorites",
-				"favorite": false, // ← now returns false
+				"favorite": false,
 			})
 			return
 		}
 
-		// ✅ Add to favorites
-		favorite := models.Favorite{
-			ID:        primitive.NewObjectID(),
-			UserID:    userID,
-			HubID:     hubID,
-			CreatedAt: time.Now(),
+		// ✅ Add to favorites, visible hubs only (as AddFavorite)
+		err = cfg.MongoClient.Database(cfg.DBName).Collection("hubs").FindOne(ctx, bson.M{
+			"_id":         hubID,
+			"merged_into": bson.M{"$exists": false},
+			"hidden":      bson.M{"$ne": true},
+		}).Err()
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
 		}
-
-		if _, err := col.InsertOne(ctx, favorite); err != nil {
+		if _, err := addFavorite(ctx, cfg, userID, hubID); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not mark as favorite"})
 			return
 		}
 
 		c.JSON(http.StatusOK, gin.H{
 			"message":  "added to favorites",
-			"favorite": true, // ← now returns true
+			"favorite": true,
 		})
 	}
 }
 
-
-
+// ListFavorites returns the caller's favorite hubs, most recently
+// favorited first (?page=&limit=)
 func ListFavorites(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userIDHex := c.GetString("user_id")
@@ -592,40 +978,464 @@ func ListFavorites(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
+		page, limit := pagination(c, 20, 100)
+
 		favCol := cfg.MongoClient.Database(cfg.DBName).Collection("favorites")
-		hubCol := cfg.MongoClient.Database(cfg.DBName).Collection("hubs")
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
-		cursor, err := favCol.Find(ctx, bson.M{"user_id": userID})
+		// Hidden and merged hubs drop out before paging, so they count
+		// towards neither the page nor the total
+		cursor, err := favCol.Aggregate(ctx, bson.A{
+			bson.M{"$match": bson.M{"user_id": userID}},
+			bson.M{"$sort": bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+			bson.M{"$lookup": bson.M{
+				"from":         "hubs",
+				"localField":   "hub_id",
+				"foreignField": "_id",
+				"as":           "hub",
+			}},
+			bson.M{"$unwind": "$hub"},
+			bson.M{"$match": bson.M{
+				"hub.merged_into": bson.M{"$exists": false},
+				"hub.hidden":      bson.M{"$ne": true},
+			}},
+			bson.M{"$facet": bson.M{
+				"hubs": bson.A{
+					bson.M{"$skip": int64((page - 1) * limit)},
+					bson.M{"$limit": int64(limit)},
+					bson.M{"$replaceRoot": bson.M{"newRoot": "$hub"}},
+				},
+				"total": bson.A{bson.M{"$count": "n"}},
+			}},
+		})
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch favorites"})
 			return
 		}
 
-		var favs []models.Favorite
-		if err := cursor.All(ctx, &favs); err != nil {
+		var result []struct {
+			Hubs  []models.Hub `bson:"hubs"`
+			Total []struct {
+				N int64 `bson:"n"`
+			} `bson:"total"`
+		}
+		if err := cursor.All(ctx, &result); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not decode favorites"})
 			return
 		}
 
-		hubIDs := []primitive.ObjectID{}
-		for _, f := range favs {
-			hubIDs = append(hubIDs, f.HubID)
+		hubs := []models.Hub{}
+		var total int64
+		if len(result) > 0 {
+			for _, h := range result[0].Hubs {
+				h.IsFavorite = true
+				hubs = append(hubs, h)
+			}
+			if len(result[0].Total) > 0 {
+				total = result[0].Total[0].N
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"hubs":  hubs,
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		})
+	}
+}
+
+// keepImages returns the existing images the client listed, matched by asset ID or URL
+func keepImages(existing []models.Asset, keep []string) []models.Asset {
+	wanted := map[string]bool{}
+	for _, k := range keep {
+		wanted[k] = true
+	}
+
+	kept := []models.Asset{}
+	for _, img := range existing {
+		if wanted[img.URL] || (!img.ID.IsZero() && wanted[img.ID.Hex()]) {
+			kept = append(kept, img)
+		}
+	}
+	return kept
+}
+
+// syncGalleryUpdate records the images UpdateHub added, drops the ones it
+// removed and rebuilds Hub.Images from the gallery
+func syncGalleryUpdate(ctx context.Context, cfg *config.Config, existing models.Hub, kept, added []models.Asset) error {
+	stay := map[primitive.ObjectID]bool{}
+	for _, img := range kept {
+		stay[img.ID] = true
+	}
+	removed := []models.Asset{}
+	for _, img := range existing.Images {
+		if !stay[img.ID] {
+			removed = append(removed, img)
+		}
+	}
+
+	if err := addGalleryPhotos(ctx, cfg, existing, added); err != nil {
+		return err
+	}
+	if err := removeGalleryPhotos(ctx, cfg, existing.ID, removed); err != nil {
+		return err
+	}
+	return rebuildHubImages(ctx, cfg, existing.ID)
+}
+
+// removeHub deletes a hub with its gallery records and stored images. It
+// reports false if the hub was already gone.
+func removeHub(ctx context.Context, cfg *config.Config, hub models.Hub) (bool, error) {
+	db := cfg.MongoClient.Database(cfg.DBName)
+
+	res, err := db.Collection("hubs").DeleteOne(ctx, bson.M{"_id": hub.ID})
+	if err != nil {
+		return false, err
+	}
+	if res.DeletedCount == 0 {
+		return false, nil
+	}
+
+	// 🔹 Drop gallery records; queued photos become unreferenced and are
+	// collected by the asset reconciler
+	if _, err := db.Collection("hub_photos").DeleteMany(ctx, bson.M{"hub_id": hub.ID}); err != nil {
+		log.Printf("⚠️ could not delete photos of hub %s: %v", hub.ID.Hex(), err)
+	}
+
+	if _, err := db.Collection("favorites").DeleteMany(ctx, bson.M{"hub_id": hub.ID}); err != nil {
+		log.Printf("⚠️ could not delete favorites of hub %s: %v", hub.ID.Hex(), err)
+	}
+	if _, err := db.Collection("collections").UpdateMany(ctx,
+		bson.M{"items.hub_id": hub.ID},
+		bson.M{"$pull": bson.M{"items": bson.M{"hub_id": hub.ID}}},
+	); err != nil {
+		log.Printf("⚠️ could not remove hub %s from collections: %v", hub.ID.Hex(), err)
+	}
+
+	// 🔹 Delete images from storage; anything that fails stays recorded
+	// and is retried by the asset reconciler
+	for _, img := range hub.Images {
+		if err := utils.DeleteAsset(cfg, img); err != nil {
+			log.Printf("⚠️ could not delete image %s: %v", img.URL, err)
 		}
+	}
+	return true, nil
+}
 
-		cursor, err = hubCol.Find(ctx, bson.M{"_id": bson.M{"$in": hubIDs}})
+// addFavorite records the favorite unless it exists, keeping the hub's
+// favorites_count in step. It reports whether a favorite was added.
+func addFavorite(ctx context.Context, cfg *config.Config, userID, hubID primitive.ObjectID) (bool, error) {
+	db := cfg.MongoClient.Database(cfg.DBName)
+
+	res, err := db.Collection("favorites").UpdateOne(ctx,
+		bson.M{"user_id": userID, "hub_id": hubID},
+		bson.M{"$setOnInsert": bson.M{"_id": primitive.NewObjectID(), "created_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil // a concurrent request added it first
+	}
+	if err != nil || res.UpsertedCount == 0 {
+		return false, err
+	}
+
+	if _, err := db.Collection("hubs").UpdateOne(ctx, bson.M{"_id": hubID}, bson.M{"$inc": bson.M{"favorites_count": 1}}); err != nil {
+		log.Printf("⚠️ could not update favorites count of hub %s: %v", hubID.Hex(), err)
+	}
+	return true, nil
+}
+
+// removeFavorite deletes the favorite if it exists, keeping the hub's
+// favorites_count in step. It reports whether a favorite was removed.
+func removeFavorite(ctx context.Context, cfg *config.Config, userID, hubID primitive.ObjectID) (bool, error) {
+	db := cfg.MongoClient.Database(cfg.DBName)
+
+	res, err := db.Collection("favorites").DeleteOne(ctx, bson.M{"user_id": userID, "hub_id": hubID})
+	if err != nil || res.DeletedCount == 0 {
+		return false, err
+	}
+
+	if _, err := db.Collection("hubs").UpdateOne(ctx,
+		bson.M{"_id": hubID, "favorites_count": bson.M{"$gt": 0}},
+		bson.M{"$inc": bson.M{"favorites_count": -1}},
+	); err != nil {
+		log.Printf("⚠️ could not update favorites count of hub %s: %v", hubID.Hex(), err)
+	}
+	return true, nil
+}
+
+// favoritesCount reads a hub's favorites_count for responses
+func favoritesCount(ctx context.Context, cfg *config.Config, hubID primitive.ObjectID) int {
+	var hub struct {
+		FavoritesCount int `bson:"favorites_count"`
+	}
+	_ = cfg.MongoClient.Database(cfg.DBName).Collection("hubs").
+		FindOne(ctx, bson.M{"_id": hubID}, options.FindOne().SetProjection(bson.M{"favorites_count": 1})).
+		Decode(&hub)
+	return hub.FavoritesCount
+}
+
+// fileScreeningReport puts a review held by screening into the moderation
+// queue, reusing the open automatic report for it if there is one.
+func fileScreeningReport(ctx context.Context, cfg *config.Config, review models.Review) error {
+	details := make([]string, 0, len(review.Flags))
+	for _, f := range review.Flags {
+		details = append(details, f.Check+": "+f.Detail)
+	}
+
+	_, err := cfg.MongoClient.Database(cfg.DBName).Collection("reports").UpdateOne(ctx,
+		bson.M{
+			"target_type": models.ReportTargetReview,
+			"target_id":   review.ID,
+			"reporter_id": primitive.NilObjectID,
+			"status":      models.ReportStatusOpen,
+		},
+		bson.M{
+			"$set": bson.M{
+				"reason":  review.Flags[0].Reason,
+				"details": strings.Join(details, "; "),
+			},
+			"$setOnInsert": bson.M{
+				"hub_id":     review.HubID,
+				"created_at": time.Now(),
+			},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// visibleReviews matches a hub's published, unhidden reviews
+func visibleReviews(hubID primitive.ObjectID) bson.M {
+	return bson.M{
+		"hub_id": hubID,
+		"hidden": bson.M{"$ne": true},
+		"status": bson.M{"$ne": models.ReviewStatusPending},
+	}
+}
+
+// hubListFilter builds the hub query shared by ListHubs and friends from
+// ?q= (full-text), ?amenities=, ?neighbourhood=, ?categories=, ?tags=, ?price_band=,
+// ?location_mismatch=true, ?neighbourhood_mismatch=true, ?min_download_mbps=, ?min_upload_mbps= and ?max_latency_ms=
+func hubListFilter(c *gin.Context, cfg *config.Config) (bson.M, error) {
+	filter := bson.M{"merged_into": bson.M{"$exists": false}, "hidden": bson.M{"$ne": true}}
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		filter["$text"] = bson.M{"$search": q}
+	}
+	if v := c.QueryArray("amenities"); len(v) > 0 {
+		amenities, err := parseAmenities(v)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch hubs"})
-			return
+			return nil, err
+		}
+		if len(amenities) > 0 {
+			filter["amenities"] = bson.M{"$all": amenities}
 		}
+	}
+	if v := c.Query("neighbourhood"); v != "" {
+		filter["neighbourhood"] = bson.M{"$in": normalizedTerms([]string{v})}
+	}
+	if v := c.QueryArray("categories"); len(v) > 0 {
+		filter["categories"] = bson.M{"$in": normalizedTerms(v)}
+	}
+	if v := c.QueryArray("tags"); len(v) > 0 {
+		// hubs store canonical tags, so filter by an alias's canonical tag too
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		tags, err := utils.ResolveTags(ctx, cfg, v)
+		if err != nil {
+			return nil, fmt.Errorf("could not resolve tags: %w", err)
+		}
+		if len(tags) > 0 {
+			filter["tags"] = bson.M{"$all": tags}
+		}
+	}
+	if c.Query("location_mismatch") == "true" {
+		filter["location_mismatch"] = true
+	}
+	if c.Query("neighbourhood_mismatch") == "true" {
+		filter["neighbourhood_mismatch"] = true
+	}
+	if v := c.Query("price_band"); v != "" {
+		bands := splitList([]string{v})
+		for _, b := range bands {
+			if !models.IsValidPriceBand(b) {
+				return nil, fmt.Errorf("unknown price_band %q", b)
+			}
+		}
+		filter["price_band"] = bson.M{"$in": bands}
+	}
 
-		var hubs []models.Hub
-		if err := cursor.All(ctx, &hubs); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not decode hubs"})
-			return
+	ranges := []struct {
+		param, field, op string
+	}{
+		{"min_download_mbps", "wifi.download_mbps", "$gte"},
+		{"min_upload_mbps", "wifi.upload_mbps", "$gte"},
+		{"max_latency_ms", "wifi.latency_ms", "$lte"},
+	}
+	for _, r := range ranges {
+		v := c.Query(r.param)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%s must be a positive number", r.param)
 		}
+		filter[r.field] = bson.M{r.op: n}
+	}
+	return filter, nil
+}
 
-		c.JSON(http.StatusOK, hubs)
+// hubListSorts maps ?sort= values to sort orders; newest first by default
+var hubListSorts = map[string]bson.D{
+	"newest":   {{Key: "created_at", Value: -1}},
+	"popular":  {{Key: "favorites_count", Value: -1}, {Key: "_id", Value: 1}},
+	"download": {{Key: "wifi.download_mbps", Value: -1}, {Key: "_id", Value: 1}},
+	"upload":   {{Key: "wifi.upload_mbps", Value: -1}, {Key: "_id", Value: 1}},
+	"latency":  {{Key: "wifi.latency_ms", Value: 1}, {Key: "_id", Value: 1}},
+}
+
+// geocodeUpdate is the $set for a hub's derived address, time zone and
+// mismatch flags. Hubs outside every boundary keep their time zone.
+func geocodeUpdate(h models.Hub) bson.M {
+	tz := h.TimeZone
+	utils.GeocodeHub(&h)
+	if h.TimeZone == "" {
+		h.TimeZone = tz
+	}
+	return bson.M{
+		"address":                h.Address,
+		"timezone":               h.TimeZone,
+		"location_mismatch":      h.LocationMismatch,
+		"neighbourhood_mismatch": h.NeighbourhoodMismatch,
+	}
+}
+
+// queryOrigin reads an optional ?lat=&lng= location
+func queryOrigin(c *gin.Context) (*models.Coordinates, error) {
+	if c.Query("lat") == "" && c.Query("lng") == "" {
+		return nil, nil
+	}
+	lat, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+	if errLat != nil || errLng != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return nil, fmt.Errorf("lat and lng must be sent together as valid coordinates")
+	}
+	return &models.Coordinates{Lat: lat, Lng: lng}, nil
+}
+
+// splitList flattens repeated and comma-separated values, lowercased and
+// without blanks or repeats
+func splitList(values []string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			part = strings.ToLower(strings.TrimSpace(part))
+			if part == "" || seen[part] {
+				continue
+			}
+			seen[part] = true
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// normalizedTerms turns repeated or comma-separated query values into
+// taxonomy slugs or tags
+func normalizedTerms(values []string) []string {
+	terms := []string{}
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if t := utils.NormalizeTag(part); t != "" {
+				terms = append(terms, t)
+			}
+		}
+	}
+	return terms
+}
+
+var errTooManyTags = fmt.Errorf("a hub can have at most %d tags", utils.MaxHubTags)
+
+// hubTerms validates the categories and neighbourhood against the
+// taxonomy and resolves tag synonyms
+func hubTerms(ctx context.Context, cfg *config.Config, categories []string, neighbourhood string, rawTags []string) ([]string, string, []string, error) {
+	cats, nb, err := resolveHubTaxonomy(ctx, cfg, categories, neighbourhood)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	tags, err := utils.ResolveTags(ctx, cfg, rawTags)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if len(tags) > utils.MaxHubTags {
+		return nil, "", nil, errTooManyTags
+	}
+	return cats, nb, tags, nil
+}
+
+// parseAmenities normalizes and validates amenity IDs
+func parseAmenities(values []string) ([]string, error) {
+	amenities := splitList(values)
+	for _, a := range amenities {
+		if !models.IsValidAmenity(a) {
+			return nil, fmt.Errorf("unknown amenity %q", a)
+		}
+	}
+	return amenities, nil
+}
+
+// FacetCount is how many matching hubs have one facet value
+type FacetCount struct {
+	Value string `bson:"_id" json:"value"`
+	Count int    `bson:"count" json:"count"`
+}
+
+// hubFacets counts amenities, neighbourhoods, categories and price bands
+// over every hub matching filter
+func hubFacets(ctx context.Context, cfg *config.Config, filter bson.M) (map[string][]FacetCount, error) {
+	countBy := func(field string) bson.A {
+		return bson.A{
+			bson.M{"$match": bson.M{field: bson.M{"$nin": bson.A{nil, ""}}}},
+			bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		}
+	}
+	amenities := append(bson.A{bson.M{"$unwind": "$amenities"}}, countBy("amenities")...)
+
+	cursor, err := cfg.MongoClient.Database(cfg.DBName).Collection("hubs").Aggregate(ctx, bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$facet": bson.M{
+			"amenities":      amenities,
+			"neighbourhoods": countBy("neighbourhood"),
+			"categories":     append(bson.A{bson.M{"$unwind": "$categories"}}, countBy("categories")...),
+			"price_bands":    countBy("price_band"),
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result []map[string][]FacetCount
+	if err := cursor.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	facets := map[string][]FacetCount{"amenities": {}, "neighbourhoods": {}, "categories": {}, "price_bands": {}}
+	if len(result) > 0 {
+		for k, v := range result[0] {
+			if v != nil {
+				facets[k] = v
+			}
+		}
+	}
+	return facets, nil
+}
+
+func hubListSort(c *gin.Context) bson.D {
+	if sort, ok := hubListSorts[c.Query("sort")]; ok {
+		return sort
 	}
+	return hubListSorts["newest"]
 }
@@ -0,0 +1,453 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+// ---------------- CREATE REPORT ----------------
+// CreateReport flags a hub, review, photo or user for moderators
+func CreateReport(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reporterID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		var input struct {
+			TargetType string `json:"target_type" binding:"required"`
+			TargetID   string `json:"target_id" binding:"required"`
+			Reason     string `json:"reason" binding:"required"`
+			Details    string `json:"details" binding:"max=1000"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !models.IsValidReportTarget(input.TargetType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "target_type must be one of hub, review, photo, user"})
+			return
+		}
+		if !models.IsValidReportReason(input.Reason) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown reason"})
+			return
+		}
+		targetID, err := primitive.ObjectIDFromHex(input.TargetID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid target id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		target, err := loadReportTarget(ctx, cfg, input.TargetType, targetID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": input.TargetType + " not found"})
+			return
+		}
+		if target.AuthorID == reporterID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "you cannot report your own content"})
+			return
+		}
+
+		col := cfg.MongoClient.Database(cfg.DBName).Collection("reports")
+		err = col.FindOne(ctx, bson.M{
+			"target_type": input.TargetType,
+			"target_id":   targetID,
+			"reporter_id": reporterID,
+			"status":      models.ReportStatusOpen,
+		}).Err()
+		if err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "you already reported this"})
+			return
+		}
+
+		report := models.Report{
+			ID:         primitive.NewObjectID(),
+			TargetType: input.TargetType,
+			TargetID:   targetID,
+			HubID:      target.HubID,
+			ReporterID: reporterID,
+			Reason:     input.Reason,
+			Details:    input.Details,
+			Status:     models.ReportStatusOpen,
+			CreatedAt:  time.Now(),
+		}
+		if _, err := col.InsertOne(ctx, report); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save report"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, report)
+	}
+}
+
+// ---------------- LIST REPORTS ----------------
+// ListReports is the moderator queue (?status=open|actioned|dismissed,
+// ?target_type=, ?page=, ?limit=), oldest first so nothing starves.
+func ListReports(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isModerator(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		filter := bson.M{"status": c.DefaultQuery("status", models.ReportStatusOpen)}
+		if t := c.Query("target_type"); t != "" {
+			filter["target_type"] = t
+		}
+		page, limit := pagination(c, 50, 200)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		col := cfg.MongoClient.Database(cfg.DBName).Collection("reports")
+		opts := options.Find().
+			SetSort(bson.M{"created_at": 1}).
+			SetSkip(int64((page - 1) * limit)).
+			SetLimit(int64(limit))
+		cursor, err := col.Find(ctx, filter, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch reports"})
+			return
+		}
+		reports := []models.Report{}
+		if err := cursor.All(ctx, &reports); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not decode reports"})
+			return
+		}
+		total, _ := col.CountDocuments(ctx, filter)
+
+		c.JSON(http.StatusOK, gin.H{
+			"reports": reports,
+			"page":    page,
+			"limit":   limit,
+			"total":   total,
+		})
+	}
+}
+
+// ---------------- GET REPORT ----------------
+// GetReport returns a report with the reported content and every other
+// report against the same target.
+func GetReport(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isModerator(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+
+		reportID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		col := cfg.MongoClient.Database(cfg.DBName).Collection("reports")
+		var report models.Report
+		if err := col.FindOne(ctx, bson.M{"_id": reportID}).Decode(&report); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "report not found"})
+			return
+		}
+
+		related := []models.Report{}
+		cursor, err := col.Find(ctx, bson.M{
+			"target_type": report.TargetType,
+			"target_id":   report.TargetID,
+			"_id":         bson.M{"$ne": report.ID},
+		}, options.Find().SetSort(bson.M{"created_at": -1}))
+		if err == nil {
+			_ = cursor.All(ctx, &related)
+		}
+
+		var content interface{}
+		if target, err := loadReportTarget(ctx, cfg, report.TargetType, report.TargetID); err == nil {
+			content = target.Content
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"report":  report,
+			"target":  content,
+			"related": related,
+		})
+	}
+}
+
+// ---------------- RESOLVE REPORT ----------------
+// ResolveReport applies a moderator action and closes every open report on
+// the same target. hide/delete may also warn the author with warn_user.
+// Dismissing a report on a review held by screening publishes it.
+func ResolveReport(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isModerator(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+			return
+		}
+		moderatorID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		reportID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report id"})
+			return
+		}
+
+		var input struct {
+			Action   string `json:"action" binding:"required,oneof=hide delete warn dismiss"`
+			WarnUser bool   `json:"warn_user"`
+			Note     string `json:"note" binding:"max=1000"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		col := cfg.MongoClient.Database(cfg.DBName).Collection("reports")
+		var report models.Report
+		if err := col.FindOne(ctx, bson.M{"_id": reportID}).Decode(&report); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "report not found"})
+			return
+		}
+		if report.Status != models.ReportStatusOpen {
+			c.JSON(http.StatusConflict, gin.H{"error": "report was already " + report.Status})
+			return
+		}
+
+		warn := input.Action == models.ModerationWarn || (input.WarnUser && input.Action != models.ModerationDismiss)
+		status := models.ReportStatusActioned
+		if input.Action == models.ModerationDismiss {
+			status = models.ReportStatusDismissed
+		}
+
+		target, err := loadReportTarget(ctx, cfg, report.TargetType, report.TargetID)
+		gone := err != nil
+		if gone {
+			if status == models.ReportStatusActioned {
+				c.JSON(http.StatusGone, gin.H{"error": "reported content no longer exists; dismiss the report instead"})
+				return
+			}
+			// dismissing a report on deleted content just closes it
+			err = nil
+		}
+
+		switch input.Action {
+		case models.ModerationHide:
+			err = hideReportTarget(ctx, cfg, target)
+		case models.ModerationDelete:
+			err = deleteReportTarget(ctx, cfg, target)
+		case models.ModerationDismiss:
+			if !gone && target.Type == models.ReportTargetReview {
+				err = publishReview(ctx, cfg, target.ID)
+			}
+		}
+		if errors.Is(err, errUnsupportedAction) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("cannot %s a %s", input.Action, report.TargetType)})
+			return
+		}
+		if err != nil {
+			log.Printf("❌ moderation %s on %s %s failed: %v", input.Action, report.TargetType, report.TargetID.Hex(), err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not apply action"})
+			return
+		}
+
+		if warn {
+			if err := warnUser(ctx, cfg, target.AuthorID, report.Reason); err != nil {
+				log.Printf("⚠️ could not warn user %s: %v", target.AuthorID.Hex(), err)
+			}
+		}
+
+		// --- Close every open report on this target ---
+		filter := bson.M{
+			"target_type": report.TargetType,
+			"target_id":   report.TargetID,
+			"status":      models.ReportStatusOpen,
+		}
+		var reporters []primitive.ObjectID
+		if ids, err := col.Distinct(ctx, "reporter_id", filter); err == nil {
+			for _, id := range ids {
+				if oid, ok := id.(primitive.ObjectID); ok && !oid.IsZero() {
+					reporters = append(reporters, oid)
+				}
+			}
+		}
+
+		now := time.Now()
+		_, err = col.UpdateMany(ctx, filter, bson.M{"$set": bson.M{
+			"status":         status,
+			"action":         input.Action,
+			"warned_user":    warn,
+			"moderator_id":   moderatorID,
+			"moderator_note": input.Note,
+			"resolved_at":    now,
+		}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not close reports"})
+			return
+		}
+
+		outcome := "no action was needed"
+		if status == models.ReportStatusActioned {
+			outcome = "we took action"
+		}
+		go utils.CreateNotification(cfg, reporters,
+			"Your report was reviewed",
+			fmt.Sprintf("Thanks for reporting this %s. A moderator reviewed it and %s.", report.TargetType, outcome))
+
+		report.Status = status
+		report.Action = input.Action
+		report.WarnedUser = warn
+		report.ModeratorID = &moderatorID
+		report.ModeratorNote = input.Note
+		report.ResolvedAt = &now
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// =============================
+// Helpers
+// =============================
+
+var errUnsupportedAction = errors.New("action not supported for this target")
+
+// reportTarget is reported content resolved for moderation
+type reportTarget struct {
+	Type     string
+	ID       primitive.ObjectID
+	AuthorID primitive.ObjectID
+	HubID    *primitive.ObjectID
+	Content  interface{}
+}
+
+// loadReportTarget fetches the reported content and who is responsible for it
+func loadReportTarget(ctx context.Context, cfg *config.Config, targetType string, id primitive.ObjectID) (reportTarget, error) {
+	db := cfg.MongoClient.Database(cfg.DBName)
+	t := reportTarget{Type: targetType, ID: id}
+
+	switch targetType {
+	case models.ReportTargetHub:
+		var hub models.Hub
+		if err := db.Collection("hubs").FindOne(ctx, bson.M{"_id": id}).Decode(&hub); err != nil {
+			return t, err
+		}
+		t.AuthorID, t.HubID, t.Content = hub.UserID, &hub.ID, hub
+	case models.ReportTargetReview:
+		var review models.Review
+		if err := db.Collection("reviews").FindOne(ctx, bson.M{"_id": id}).Decode(&review); err != nil {
+			return t, err
+		}
+		t.AuthorID, t.HubID, t.Content = review.UserID, &review.HubID, review
+	case models.ReportTargetPhoto:
+		var photo models.HubPhoto
+		if err := db.Collection("hub_photos").FindOne(ctx, bson.M{"_id": id}).Decode(&photo); err != nil {
+			return t, err
+		}
+		t.AuthorID, t.HubID, t.Content = photo.UploadedBy, &photo.HubID, photo
+	case models.ReportTargetUser:
+		var user models.User
+		if err := db.Collection("users").FindOne(ctx, bson.M{"_id": id}).Decode(&user); err != nil {
+			return t, err
+		}
+		t.AuthorID, t.Content = user.ID, gin.H{"id": user.ID, "name": user.Name, "warnings": user.Warnings, "created_at": user.CreatedAt}
+	default:
+		return t, mongo.ErrNoDocuments
+	}
+	return t, nil
+}
+
+// hideReportTarget hides content from everyone but moderators
+func hideReportTarget(ctx context.Context, cfg *config.Config, t reportTarget) error {
+	db := cfg.MongoClient.Database(cfg.DBName)
+	hide := bson.M{"$set": bson.M{"hidden": true}}
+
+	switch t.Type {
+	case models.ReportTargetHub:
+		_, err := db.Collection("hubs").UpdateOne(ctx, bson.M{"_id": t.ID}, hide)
+		return err
+	case models.ReportTargetReview:
+		_, err := db.Collection("reviews").UpdateOne(ctx, bson.M{"_id": t.ID}, hide)
+		return err
+	case models.ReportTargetPhoto:
+		if _, err := db.Collection("hub_photos").UpdateOne(ctx, bson.M{"_id": t.ID}, hide); err != nil {
+			return err
+		}
+		return rebuildHubImages(ctx, cfg, *t.HubID)
+	}
+	return errUnsupportedAction
+}
+
+// deleteReportTarget removes content for good
+func deleteReportTarget(ctx context.Context, cfg *config.Config, t reportTarget) error {
+	db := cfg.MongoClient.Database(cfg.DBName)
+
+	switch t.Type {
+	case models.ReportTargetHub:
+		_, err := removeHub(ctx, cfg, t.Content.(models.Hub))
+		return err
+	case models.ReportTargetReview:
+		_, err := db.Collection("reviews").DeleteOne(ctx, bson.M{"_id": t.ID})
+		return err
+	case models.ReportTargetPhoto:
+		photo := t.Content.(models.HubPhoto)
+		if _, err := db.Collection("hub_photos").DeleteOne(ctx, bson.M{"_id": photo.ID}); err != nil {
+			return err
+		}
+		if err := rebuildHubImages(ctx, cfg, photo.HubID); err != nil {
+			return err
+		}
+		// anything that fails stays recorded and is retried by the asset reconciler
+		if err := utils.DeleteAsset(cfg, photo.Asset); err != nil {
+			log.Printf("⚠️ could not delete image %s: %v", photo.Asset.URL, err)
+		}
+		return nil
+	}
+	return errUnsupportedAction
+}
+
+// publishReview releases a review held by screening once it is cleared
+func publishReview(ctx context.Context, cfg *config.Config, id primitive.ObjectID) error {
+	_, err := cfg.MongoClient.Database(cfg.DBName).Collection("reviews").UpdateOne(ctx,
+		bson.M{"_id": id, "status": models.ReviewStatusPending},
+		bson.M{"$set": bson.M{"status": models.ReviewStatusPublished}},
+	)
+	return err
+}
+
+// warnUser records a guideline warning against a user and tells them
+func warnUser(ctx context.Context, cfg *config.Config, userID primitive.ObjectID, reason string) error {
+	_, err := cfg.MongoClient.Database(cfg.DBName).Collection("users").UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{"$inc": bson.M{"warnings": 1}, "$set": bson.M{"updated_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	return utils.CreateNotification(cfg, []primitive.ObjectID{userID},
+		"Community guidelines warning",
+		fmt.Sprintf("Content you posted was reported (%s) and found to break our community guidelines. Repeated violations may lead to your account being suspended.", reason))
+}
@@ -46,7 +46,7 @@ func RequestOTP(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		go utils.SendEmail(input.Email, "Your OTP Code", "Your OTP is: "+otp)
+		go utils.SendOTPEmail(user, otp, "login", 10*time.Minute)
 
 		c.JSON(http.StatusOK, gin.H{"message": "OTP sent to email"})
 	}
@@ -0,0 +1,259 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+// maxOpeningPeriods caps a hub's opening hours: three periods a day
+const maxOpeningPeriods = 21
+
+// ---------------- HOURS ----------------
+// SetHubHours replaces a hub's opening hours (verified owner or moderator).
+// An empty list clears them. The change is recorded in the hub's history.
+func SetHubHours(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input struct {
+			Hours []models.OpeningPeriod `json:"hours"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := validateOpeningHours(input.Hours); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if input.Hours == nil {
+			input.Hours = []models.OpeningPeriod{}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		hub, ok := loadOwnedHub(c, ctx, cfg, "only the hub's verified owner can set opening hours")
+		if !ok {
+			return
+		}
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		next := hub.Fields()
+		next.Hours = input.Hours
+		if len(hub.Fields().Diff(next)) > 0 {
+			if _, err := applyHubFields(ctx, cfg, hub, next, userID, models.RevisionSourceOwnerEdit, nil, nil); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save opening hours"})
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"hours":         next.Hours,
+			"opening_hours": models.FormatOpeningHours(next.Hours),
+		})
+	}
+}
+
+// ---------------- OWNER REPLIES ----------------
+// ReplyToReview posts or edits the verified owner's public reply to a
+// review. The reviewer is notified of the first reply.
+func ReplyToReview(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+		reviewID, err := primitive.ObjectIDFromHex(c.Param("reviewId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid review id"})
+			return
+		}
+		var input struct {
+			Text string `json:"text" binding:"required,max=2000"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		text := strings.TrimSpace(input.Text)
+		if text == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "text cannot be empty"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		hub, ok := loadOwnedHub(c, ctx, cfg, "only the hub's verified owner can reply to reviews")
+		if !ok {
+			return
+		}
+		if !isVerifiedOwner(c, hub) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only the hub's verified owner can reply to reviews"})
+			return
+		}
+
+		col := cfg.MongoClient.Database(cfg.DBName).Collection("reviews")
+		var review models.Review
+		if err := col.FindOne(ctx, visibleReview(hub.ID, reviewID)).Decode(&review); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "review not found"})
+			return
+		}
+
+		now := time.Now()
+		reply := models.OwnerReply{UserID: userID, Text: text, CreatedAt: now, UpdatedAt: now}
+		if review.OwnerReply != nil {
+			reply.CreatedAt = review.OwnerReply.CreatedAt
+		}
+		if _, err := col.UpdateOne(ctx, bson.M{"_id": review.ID}, bson.M{"$set": bson.M{"owner_reply": reply}}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save reply"})
+			return
+		}
+
+		if review.OwnerReply == nil && review.UserID != userID {
+			go notifyReviewReply(cfg, review.UserID, hub, userID, text)
+		}
+
+		review.OwnerReply = &reply
+		c.JSON(http.StatusOK, review)
+	}
+}
+
+// DeleteReviewReply removes the owner's reply (verified owner or moderator)
+func DeleteReviewReply(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reviewID, err := primitive.ObjectIDFromHex(c.Param("reviewId"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid review id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		hub, ok := loadOwnedHub(c, ctx, cfg, "only the hub's verified owner or a moderator can remove replies")
+		if !ok {
+			return
+		}
+
+		res, err := cfg.MongoClient.Database(cfg.DBName).Collection("reviews").UpdateOne(ctx,
+			bson.M{"_id": reviewID, "hub_id": hub.ID, "owner_reply": bson.M{"$exists": true}},
+			bson.M{"$unset": bson.M{"owner_reply": ""}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not remove reply"})
+			return
+		}
+		if res.MatchedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "reply not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "reply removed"})
+	}
+}
+
+// =============================
+// Helpers
+// =============================
+
+// loadOwnedHub resolves :id for its verified owner or a moderator,
+// writing the error response (with denied on 403) itself
+func loadOwnedHub(c *gin.Context, ctx context.Context, cfg *config.Config, denied string) (models.Hub, bool) {
+	var hub models.Hub
+	hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+		return hub, false
+	}
+	err = cfg.MongoClient.Database(cfg.DBName).Collection("hubs").FindOne(ctx, bson.M{
+		"_id":         hubID,
+		"merged_into": bson.M{"$exists": false},
+	}).Decode(&hub)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+		return hub, false
+	}
+	if !isVerifiedOwner(c, hub) && !isModerator(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": denied})
+		return hub, false
+	}
+	return hub, true
+}
+
+// validateOpeningHours checks each period and the number of periods
+func validateOpeningHours(hours []models.OpeningPeriod) error {
+	if len(hours) > maxOpeningPeriods {
+		return fmt.Errorf("at most %d opening periods", maxOpeningPeriods)
+	}
+	for _, p := range hours {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visibleReview matches one published, unhidden review of a hub
+func visibleReview(hubID, reviewID primitive.ObjectID) bson.M {
+	f := visibleReviews(hubID)
+	f["_id"] = reviewID
+	return f
+}
+
+// notifyReviewReply tells a reviewer the owner answered, in the app and by
+// email. The reply is signed with the business name from the verified
+// claim, or the owner's name.
+func notifyReviewReply(cfg *config.Config, reviewerID primitive.ObjectID, hub models.Hub, ownerID primitive.ObjectID, reply string) {
+	if err := utils.CreateNotification(cfg, []primitive.ObjectID{reviewerID},
+		"The owner replied to your review",
+		fmt.Sprintf("%s replied to your review.", hub.Title)); err != nil {
+		log.Printf("⚠️ could not notify reviewer %s: %v", reviewerID.Hex(), err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	db := cfg.MongoClient.Database(cfg.DBName)
+
+	var user models.User
+	if err := db.Collection("users").FindOne(ctx, bson.M{"_id": reviewerID}).Decode(&user); err != nil {
+		log.Printf("⚠️ could not load reviewer %s: %v", reviewerID.Hex(), err)
+		return
+	}
+
+	replier := ""
+	if hub.VerifiedClaimID != nil {
+		var claim models.HubClaim
+		if err := db.Collection("hub_claims").FindOne(ctx, bson.M{"_id": *hub.VerifiedClaimID}).Decode(&claim); err == nil {
+			replier = claim.BusinessName
+		}
+	}
+	if replier == "" {
+		var owner models.User
+		if err := db.Collection("users").FindOne(ctx, bson.M{"_id": ownerID}).Decode(&owner); err == nil {
+			replier = owner.Name
+		}
+	}
+	if replier == "" {
+		replier = "The owner"
+	}
+
+	if err := utils.SendReviewReplyEmail(user, replier, hub.Title, reply); err != nil {
+		log.Printf("⚠️ could not email reviewer %s: %v", reviewerID.Hex(), err)
+	}
+}
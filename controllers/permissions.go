@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	models "github.com/phillip/contribution-tracker-go/models"
+)
+
+// validRoles are the roles an admin may grant
+var validRoles = map[string]bool{"user": true, "moderator": true, "admin": true}
+
+// isModerator reports whether the caller may moderate any content
+func isModerator(c *gin.Context) bool {
+	role := c.GetString("role")
+	return role == "admin" || role == "moderator"
+}
+
+// canManageHub reports whether the caller owns the hub or is a moderator
+func canManageHub(c *gin.Context, hub models.Hub) bool {
+	return isModerator(c) || hub.UserID.Hex() == c.GetString("user_id")
+}
+
+// isVerifiedOwner reports whether the caller is the hub's owner by an
+// approved claim
+func isVerifiedOwner(c *gin.Context, hub models.Hub) bool {
+	return hub.Verified && hub.UserID.Hex() == c.GetString("user_id")
+}
@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+const (
+	// recommendationHistory is how far back check-ins count towards taste
+	recommendationHistory = 90 * 24 * time.Hour
+	// recommendationRadiusMeters bounds the candidates around ?lat=&lng=
+	recommendationRadiusMeters = 25000.0
+	// recommendationCandidates caps the hubs scored per request, most
+	// favorited first
+	recommendationCandidates = 300
+)
+
+// ---------------- RECOMMENDED ----------------
+// RecommendHubs ranks hubs for the caller from their favorites, reviews and
+// check-ins, ?lat=&lng=, and ?prefer=fast_wifi,quiet. Callers without any
+// history get top-rated hubs near them. The ListHubs filters apply; with a
+// location only hubs within recommendationRadiusMeters are considered.
+func RecommendHubs(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		profile := utils.RecommendationProfile{
+			Preferences: map[string]bool{},
+			Affinity:    map[primitive.ObjectID]float64{},
+			Disliked:    map[primitive.ObjectID]bool{},
+		}
+
+		// --- Location ---
+		profile.Origin, err = queryOrigin(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		// --- Amenity preferences ---
+		if prefer := c.Query("prefer"); prefer != "" {
+			for _, p := range strings.Split(prefer, ",") {
+				p = strings.TrimSpace(p)
+				if p != utils.PreferFastWiFi && p != utils.PreferQuiet {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "prefer accepts fast_wifi and quiet"})
+					return
+				}
+				profile.Preferences[p] = true
+			}
+		}
+
+		filter, err := hubListFilter(c, cfg)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		_, limit := pagination(c, 20, 50)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		db := cfg.MongoClient.Database(cfg.DBName)
+
+		// --- Candidates ---
+		if profile.Origin != nil {
+			for k, v := range utils.NearbyFilter(*profile.Origin, recommendationRadiusMeters) {
+				filter[k] = v
+			}
+		}
+		opts := options.Find().
+			SetSort(bson.D{{Key: "favorites_count", Value: -1}, {Key: "_id", Value: 1}}).
+			SetLimit(recommendationCandidates)
+		cursor, err := db.Collection("hubs").Find(ctx, filter, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch hubs"})
+			return
+		}
+		var hubs []models.Hub
+		if err := cursor.All(ctx, &hubs); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not decode hubs"})
+			return
+		}
+
+		ratings, err := hubRatings(ctx, cfg, hubs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load ratings"})
+			return
+		}
+
+		quiet := map[primitive.ObjectID]bool{}
+		if profile.Preferences[utils.PreferQuiet] {
+			quietList, err := quietHubs(ctx, cfg, hubs)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load popular times"})
+				return
+			}
+			for _, h := range quietList {
+				quiet[h.ID] = true
+			}
+		}
+
+		// --- The caller's history ---
+		favorites := map[primitive.ObjectID]bool{}
+		favIDs, err := db.Collection("favorites").Distinct(ctx, "hub_id", bson.M{"user_id": userID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load favorites"})
+			return
+		}
+		for _, id := range favIDs {
+			if oid, ok := id.(primitive.ObjectID); ok {
+				favorites[oid] = true
+			}
+		}
+
+		myRatings := map[primitive.ObjectID]int{}
+		cursor, err = db.Collection("reviews").Find(ctx, bson.M{"user_id": userID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load reviews"})
+			return
+		}
+		var reviews []models.Review
+		if err := cursor.All(ctx, &reviews); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load reviews"})
+			return
+		}
+		for _, r := range reviews {
+			myRatings[r.HubID] = r.Rating
+		}
+
+		checkIns := map[primitive.ObjectID]int{}
+		cursor, err = db.Collection("checkins").Aggregate(ctx, bson.A{
+			bson.M{"$match": bson.M{"user_id": userID, "created_at": bson.M{"$gte": time.Now().Add(-recommendationHistory)}}},
+			bson.M{"$group": bson.M{"_id": "$hub_id", "count": bson.M{"$sum": 1}}},
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load check-ins"})
+			return
+		}
+		var visits []struct {
+			HubID primitive.ObjectID `bson:"_id"`
+			Count int                `bson:"count"`
+		}
+		if err := cursor.All(ctx, &visits); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load check-ins"})
+			return
+		}
+		for _, v := range visits {
+			checkIns[v.HubID] = v.Count
+		}
+
+		// --- Profile: their whole history, whatever the filters ---
+		historyIDs := map[primitive.ObjectID]bool{}
+		for id := range favorites {
+			historyIDs[id] = true
+		}
+		for id := range myRatings {
+			historyIDs[id] = true
+		}
+		for id := range checkIns {
+			historyIDs[id] = true
+		}
+		for id := range historyIDs {
+			rating, fav := myRatings[id], favorites[id]
+			if a := utils.ProfileAffinity(fav, rating, checkIns[id]); a != 0 {
+				profile.Affinity[id] = a
+			}
+			if rating > 0 && rating <= 2 && !fav {
+				profile.Disliked[id] = true
+			}
+		}
+
+		likedIDs := []primitive.ObjectID{}
+		for id := range historyIDs {
+			if favorites[id] || myRatings[id] >= 4 {
+				likedIDs = append(likedIDs, id)
+			}
+		}
+		if len(likedIDs) > 0 {
+			cursor, err = db.Collection("hubs").Find(ctx, bson.M{
+				"_id":         bson.M{"$in": likedIDs},
+				"merged_into": bson.M{"$exists": false},
+			}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load liked hubs"})
+				return
+			}
+			if err := cursor.All(ctx, &profile.Liked); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load liked hubs"})
+				return
+			}
+		}
+
+		// --- Score ---
+		candidates := make([]utils.RecommendationCandidate, 0, len(hubs))
+		for _, h := range hubs {
+			h.IsFavorite = favorites[h.ID]
+			candidates = append(candidates, utils.RecommendationCandidate{
+				Hub:       h,
+				AvgRating: ratings[h.ID].Avg,
+				Reviews:   ratings[h.ID].Count,
+				QuietNow:  quiet[h.ID],
+			})
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"recommendations": utils.RankHubs(profile, candidates, limit),
+			"cold_start":      profile.ColdStart(),
+		})
+	}
+}
+
+// hubRating is the published-review average of one hub
+type hubRating struct {
+	Avg   float64
+	Count int
+}
+
+// hubRatings averages the visible reviews of the given hubs
+func hubRatings(ctx context.Context, cfg *config.Config, hubs []models.Hub) (map[primitive.ObjectID]hubRating, error) {
+	if len(hubs) == 0 {
+		return map[primitive.ObjectID]hubRating{}, nil
+	}
+	ids := make([]primitive.ObjectID, len(hubs))
+	for i, h := range hubs {
+		ids[i] = h.ID
+	}
+	cursor, err := cfg.MongoClient.Database(cfg.DBName).Collection("reviews").Aggregate(ctx, bson.A{
+		bson.M{"$match": bson.M{
+			"hub_id": bson.M{"$in": ids},
+			"hidden": bson.M{"$ne": true},
+			"status": bson.M{"$ne": models.ReviewStatusPending},
+		}},
+		bson.M{"$group": bson.M{"_id": "$hub_id", "avg": bson.M{"$avg": "$rating"}, "count": bson.M{"$sum": 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		HubID primitive.ObjectID `bson:"_id"`
+		Avg   float64            `bson:"avg"`
+		Count int                `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+	ratings := make(map[primitive.ObjectID]hubRating, len(rows))
+	for _, r := range rows {
+		ratings[r.HubID] = hubRating{Avg: r.Avg, Count: r.Count}
+	}
+	return ratings, nil
+}
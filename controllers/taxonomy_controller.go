@@ -0,0 +1,481 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+// taxonomyKinds maps the :kind route segment to the taxonomy kind and the
+// hub field holding its slugs
+var taxonomyKinds = map[string]struct {
+	kind, hubField string
+}{
+	"categories":     {models.TaxonomyCategory, "categories"},
+	"neighbourhoods": {models.TaxonomyNeighbourhood, "neighbourhood"},
+}
+
+// ---------------- LIST TERMS ----------------
+// ListTaxonomy returns every category or neighbourhood with the number of
+// visible hubs using it
+func ListTaxonomy(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		kind, ok := taxonomyKinds[c.Param("kind")]
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "unknown taxonomy"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		db := cfg.MongoClient.Database(cfg.DBName)
+		cursor, err := db.Collection("taxonomy").Find(ctx, bson.M{"kind": kind.kind}, options.Find().SetSort(bson.M{"name": 1}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch terms"})
+			return
+		}
+		terms := []models.TaxonomyTerm{}
+		if err := cursor.All(ctx, &terms); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not decode terms"})
+			return
+		}
+
+		counts, err := countHubsBy(ctx, cfg, kind.hubField, nil)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not count hubs"})
+			return
+		}
+		byTerm := map[string]int{}
+		for _, tc := range counts {
+			byTerm[tc.Tag] = tc.HubCount
+		}
+		for i := range terms {
+			terms[i].HubCount = byTerm[terms[i].Slug]
+		}
+
+		c.JSON(http.StatusOK, terms)
+	}
+}
+
+// ---------------- CREATE TERM ----------------
+// CreateTaxonomyTerm (admin only) adds a category or neighbourhood. The
+// slug is derived from the name unless given.
+func CreateTaxonomyTerm(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only admins can curate the taxonomy"})
+			return
+		}
+		kind, ok := taxonomyKinds[c.Param("kind")]
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "unknown taxonomy"})
+			return
+		}
+		userID, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+
+		var input struct {
+			Name        string `json:"name" binding:"required,max=80"`
+			Slug        string `json:"slug" binding:"max=32"`
+			Description string `json:"description" binding:"max=500"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		slug := input.Slug
+		if slug == "" {
+			slug = input.Name
+		}
+		slug = utils.NormalizeTag(slug)
+		if slug == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name must contain letters or digits"})
+			return
+		}
+
+		now := time.Now()
+		term := models.TaxonomyTerm{
+			ID:          primitive.NewObjectID(),
+			Kind:        kind.kind,
+			Slug:        slug,
+			Name:        strings.TrimSpace(input.Name),
+			Description: strings.TrimSpace(input.Description),
+			CreatedBy:   userID,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := cfg.MongoClient.Database(cfg.DBName).Collection("taxonomy").InsertOne(ctx, term); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("%s %q already exists", kind.kind, slug)})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create term"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, term)
+	}
+}
+
+// ---------------- UPDATE TERM ----------------
+// UpdateTaxonomyTerm (admin only) renames a term. Slugs are stable so hubs
+// keep pointing at it.
+func UpdateTaxonomyTerm(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only admins can curate the taxonomy"})
+			return
+		}
+		kind, ok := taxonomyKinds[c.Param("kind")]
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "unknown taxonomy"})
+			return
+		}
+
+		var input struct {
+			Name        *string `json:"name" binding:"omitempty,max=80"`
+			Description *string `json:"description" binding:"omitempty,max=500"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		update := bson.M{"updated_at": time.Now()}
+		if input.Name != nil {
+			if strings.TrimSpace(*input.Name) == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "name cannot be empty"})
+				return
+			}
+			update["name"] = strings.TrimSpace(*input.Name)
+		}
+		if input.Description != nil {
+			update["description"] = strings.TrimSpace(*input.Description)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var term models.TaxonomyTerm
+		err := cfg.MongoClient.Database(cfg.DBName).Collection("taxonomy").FindOneAndUpdate(ctx,
+			bson.M{"kind": kind.kind, "slug": c.Param("slug")},
+			bson.M{"$set": update},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&term)
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": kind.kind + " not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not update term"})
+			return
+		}
+
+		c.JSON(http.StatusOK, term)
+	}
+}
+
+// ---------------- DELETE TERM ----------------
+// DeleteTaxonomyTerm (admin only) removes a term and unassigns it from hubs
+func DeleteTaxonomyTerm(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only admins can curate the taxonomy"})
+			return
+		}
+		kind, ok := taxonomyKinds[c.Param("kind")]
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "unknown taxonomy"})
+			return
+		}
+		slug := c.Param("slug")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		db := cfg.MongoClient.Database(cfg.DBName)
+		res, err := db.Collection("taxonomy").DeleteOne(ctx, bson.M{"kind": kind.kind, "slug": slug})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete term"})
+			return
+		}
+		if res.DeletedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": kind.kind + " not found"})
+			return
+		}
+
+		unassign := bson.M{"$pull": bson.M{"categories": slug}}
+		if kind.kind == models.TaxonomyNeighbourhood {
+			unassign = bson.M{"$unset": bson.M{"neighbourhood": "", "neighbourhood_mismatch": ""}}
+		}
+		upd, err := db.Collection("hubs").UpdateMany(ctx, bson.M{kind.hubField: slug}, unassign)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "term deleted but hubs could not be updated"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":      "Term deleted successfully",
+			"slug":         slug,
+			"hubs_updated": upd.ModifiedCount,
+		})
+	}
+}
+
+// ---------------- TAGS ----------------
+// ListTags returns user tags by number of visible hubs, optionally those
+// starting with ?q= (for autocomplete), up to ?limit=
+func ListTags(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, limit := pagination(c, 50, 200)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var match bson.M
+		if q := utils.NormalizeTag(c.Query("q")); q != "" {
+			match = bson.M{"_id": bson.M{"$regex": "^" + regexp.QuoteMeta(q)}}
+		}
+		tags, err := countHubsBy(ctx, cfg, "tags", match)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not count tags"})
+			return
+		}
+		if len(tags) > limit {
+			tags = tags[:limit]
+		}
+
+		c.JSON(http.StatusOK, tags)
+	}
+}
+
+// ListTagSynonyms returns every alias and the tag it merges into
+func ListTagSynonyms(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		cursor, err := cfg.MongoClient.Database(cfg.DBName).Collection("tag_synonyms").
+			Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "tag", Value: 1}, {Key: "alias", Value: 1}}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch synonyms"})
+			return
+		}
+		synonyms := []models.TagSynonym{}
+		if err := cursor.All(ctx, &synonyms); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not decode synonyms"})
+			return
+		}
+
+		c.JSON(http.StatusOK, synonyms)
+	}
+}
+
+// CreateTagSynonym (admin only) makes alias a synonym of tag and merges
+// the alias into tag on every hub that already uses it
+func CreateTagSynonym(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only admins can merge tags"})
+			return
+		}
+		userID, _ := primitive.ObjectIDFromHex(c.GetString("user_id"))
+
+		var input struct {
+			Alias string `json:"alias" binding:"required"`
+			Tag   string `json:"tag" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		alias, tag := utils.NormalizeTag(input.Alias), utils.NormalizeTag(input.Tag)
+		if alias == "" || tag == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "alias and tag must contain letters or digits"})
+			return
+		}
+		if alias == tag {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "a tag cannot be a synonym of itself"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		db := cfg.MongoClient.Database(cfg.DBName)
+		synCol := db.Collection("tag_synonyms")
+
+		// keep chains one level deep: point at tag's own canonical tag, and
+		// repoint aliases of alias at it
+		var target models.TagSynonym
+		if err := synCol.FindOne(ctx, bson.M{"alias": tag}).Decode(&target); err == nil {
+			tag = target.Tag
+			if tag == alias {
+				c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("%q is already a synonym of %q", input.Tag, alias)})
+				return
+			}
+		}
+
+		synonym := models.TagSynonym{
+			ID:        primitive.NewObjectID(),
+			Alias:     alias,
+			Tag:       tag,
+			CreatedBy: userID,
+			CreatedAt: time.Now(),
+		}
+		if _, err := synCol.InsertOne(ctx, synonym); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("%q is already a synonym", alias)})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save synonym"})
+			return
+		}
+		if _, err := synCol.UpdateMany(ctx, bson.M{"tag": alias}, bson.M{"$set": bson.M{"tag": tag}}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "synonym saved but older synonyms could not be repointed"})
+			return
+		}
+
+		// --- Merge the alias into tag on existing hubs ---
+		hubCol := db.Collection("hubs")
+		if _, err := hubCol.UpdateMany(ctx, bson.M{"tags": alias}, bson.M{"$addToSet": bson.M{"tags": tag}}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "synonym saved but hubs could not be updated"})
+			return
+		}
+		res, err := hubCol.UpdateMany(ctx, bson.M{"tags": alias}, bson.M{"$pull": bson.M{"tags": alias}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "synonym saved but hubs could not be updated"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"synonym":     synonym,
+			"hubs_merged": res.ModifiedCount,
+		})
+	}
+}
+
+// DeleteTagSynonym (admin only) stops mapping alias. Hubs already merged
+// keep the canonical tag.
+func DeleteTagSynonym(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "only admins can merge tags"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		alias := utils.NormalizeTag(c.Param("alias"))
+		res, err := cfg.MongoClient.Database(cfg.DBName).Collection("tag_synonyms").DeleteOne(ctx, bson.M{"alias": alias})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not delete synonym"})
+			return
+		}
+		if res.DeletedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "synonym not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Synonym deleted successfully",
+			"alias":   alias,
+		})
+	}
+}
+
+// =============================
+// Helpers
+// =============================
+
+// countHubsBy counts visible hubs per value of field (unwinding arrays),
+// most used first. match filters the grouped values.
+func countHubsBy(ctx context.Context, cfg *config.Config, field string, match bson.M) ([]models.TagCount, error) {
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{
+			"merged_into": bson.M{"$exists": false},
+			"hidden":      bson.M{"$ne": true},
+			field:         bson.M{"$nin": bson.A{nil, ""}},
+		}},
+		bson.M{"$unwind": "$" + field},
+		bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+	}
+	if match != nil {
+		pipeline = append(pipeline, bson.M{"$match": match})
+	}
+	pipeline = append(pipeline, bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}})
+
+	cursor, err := cfg.MongoClient.Database(cfg.DBName).Collection("hubs").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	counts := []models.TagCount{}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// resolveHubTaxonomy normalizes category and neighbourhood input and
+// checks that every term exists. Empty input gives empty results.
+func resolveHubTaxonomy(ctx context.Context, cfg *config.Config, categories []string, neighbourhood string) ([]string, string, error) {
+	cats := []string{}
+	seen := map[string]bool{}
+	for _, raw := range categories {
+		for _, part := range strings.Split(raw, ",") {
+			if slug := utils.NormalizeTag(part); slug != "" && !seen[slug] {
+				seen[slug] = true
+				cats = append(cats, slug)
+			}
+		}
+	}
+	nb := utils.NormalizeTag(neighbourhood)
+
+	col := cfg.MongoClient.Database(cfg.DBName).Collection("taxonomy")
+	if len(cats) > 0 {
+		n, err := col.CountDocuments(ctx, bson.M{"kind": models.TaxonomyCategory, "slug": bson.M{"$in": cats}})
+		if err != nil {
+			return nil, "", err
+		}
+		if int(n) != len(cats) {
+			return nil, "", errUnknownTerm{models.TaxonomyCategory, cats}
+		}
+	}
+	if nb != "" {
+		if err := col.FindOne(ctx, bson.M{"kind": models.TaxonomyNeighbourhood, "slug": nb}).Err(); err == mongo.ErrNoDocuments {
+			return nil, "", errUnknownTerm{models.TaxonomyNeighbourhood, []string{nb}}
+		} else if err != nil {
+			return nil, "", err
+		}
+	}
+	return cats, nb, nil
+}
+
+// errUnknownTerm is returned for categories or neighbourhoods that admins
+// haven't added
+type errUnknownTerm struct {
+	kind  string
+	slugs []string
+}
+
+func (e errUnknownTerm) Error() string {
+	return fmt.Sprintf("unknown %s among %s", e.kind, strings.Join(e.slugs, ", "))
+}
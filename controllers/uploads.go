@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+// imageUploads validates the images posted under field. On failure it writes
+// a structured 400 listing every rejected file and returns ok=false.
+func imageUploads(c *gin.Context, field string, limits utils.ImageLimits) ([]utils.ImageUpload, bool) {
+	form, err := c.MultipartForm()
+	if err != nil && err != http.ErrNotMultipart {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid form data"})
+		return nil, false
+	}
+	if form == nil {
+		return nil, true
+	}
+
+	uploads, err := utils.ValidateImages(field, form.File[field], limits)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid upload", "details": err})
+		return nil, false
+	}
+	return uploads, true
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/phillip/contribution-tracker-go/config"
 	"github.com/phillip/contribution-tracker-go/models"
+	"github.com/phillip/contribution-tracker-go/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -84,6 +85,7 @@ func UpdateUser(cfg *config.Config) gin.HandlerFunc {
 			Email string `json:"email,omitempty"`
 			Phone string `json:"phone,omitempty"`
 			Role  string `json:"role,omitempty"`
+			Locale string `json:"locale,omitempty"`
 		}
 
 		if err := c.ShouldBindJSON(&input); err != nil {
@@ -102,8 +104,23 @@ func UpdateUser(cfg *config.Config) gin.HandlerFunc {
 			update["phone"] = input.Phone
 		}
 		if input.Role != "" {
+			if c.GetString("role") != "admin" {
+				c.JSON(http.StatusForbidden, gin.H{"error": "only admins can change roles"})
+				return
+			}
+			if !validRoles[input.Role] {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid role"})
+				return
+			}
 			update["role"] = input.Role
 		}
+		if input.Locale != "" {
+			if !utils.IsSupportedLocale(input.Locale) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported locale"})
+				return
+			}
+			update["locale"] = input.Locale
+		}
 
 		col := cfg.MongoClient.Database(cfg.DBName).Collection("users")
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+// wifiTestCooldown is how long a user waits between tests at one hub
+const wifiTestCooldown = 10 * time.Minute
+
+// ---------------- SUBMIT WIFI TEST ----------------
+// SubmitWiFiTest records a speed test run at the hub. Tests must come from
+// near the hub and look plausible next to recent results; outliers are
+// stored as rejected until other users confirm the change.
+func SubmitWiFiTest(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := primitive.ObjectIDFromHex(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+			return
+		}
+
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		var input struct {
+			DownloadMbps float64   `json:"download_mbps" binding:"required"`
+			UploadMbps   float64   `json:"upload_mbps" binding:"required"`
+			LatencyMs    float64   `json:"latency_ms" binding:"required"`
+			SSID         string    `json:"ssid" binding:"max=64"`
+			MeasuredAt   time.Time `json:"measured_at" binding:"required"`
+			Lat          *float64  `json:"lat" binding:"required,min=-90,max=90"`
+			Lng          *float64  `json:"lng" binding:"required,min=-180,max=180"`
+		}
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		now := time.Now()
+		test := models.WiFiSpeedTest{
+			ID:           primitive.NewObjectID(),
+			HubID:        hubID,
+			UserID:       userID,
+			DownloadMbps: input.DownloadMbps,
+			UploadMbps:   input.UploadMbps,
+			LatencyMs:    input.LatencyMs,
+			SSID:         input.SSID,
+			Coordinates:  models.Coordinates{Lat: *input.Lat, Lng: *input.Lng},
+			MeasuredAt:   input.MeasuredAt,
+			CreatedAt:    now,
+		}
+		if reason := utils.ValidateWiFiTest(test, now); reason != "" {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": reason})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		db := cfg.MongoClient.Database(cfg.DBName)
+		var hub models.Hub
+		err = db.Collection("hubs").FindOne(ctx, bson.M{
+			"_id":         hubID,
+			"merged_into": bson.M{"$exists": false},
+			"hidden":      bson.M{"$ne": true},
+		}).Decode(&hub)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "hub not found"})
+			return
+		}
+		if hub.Coordinates.Lat == 0 && hub.Coordinates.Lng == 0 {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "this hub has no location yet, so tests can't be verified"})
+			return
+		}
+
+		test.DistanceMeters = math.Round(utils.HaversineMeters(test.Coordinates, hub.Coordinates))
+		if test.DistanceMeters > cfg.CheckIns.MaxDistanceMeters {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":           "speed tests must be run at the hub",
+				"distance_meters": test.DistanceMeters,
+			})
+			return
+		}
+
+		col := db.Collection("wifi_tests")
+		err = col.FindOne(ctx, bson.M{
+			"hub_id":     hubID,
+			"user_id":    userID,
+			"created_at": bson.M{"$gte": now.Add(-wifiTestCooldown)},
+		}).Err()
+		if err == nil {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "you already submitted a test here recently"})
+			return
+		}
+
+		recent, err := utils.RecentWiFiTests(ctx, cfg, hubID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load recent tests"})
+			return
+		}
+
+		// Outliers are kept aside; once enough users measure the same new
+		// speed it replaces the old medians
+		var shift []models.WiFiSpeedTest
+		if metric := utils.WiFiOutlier(test, recent); metric != "" {
+			rejected, err := utils.RecentRejectedWiFiTests(ctx, cfg, hubID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load recent tests"})
+				return
+			}
+			agreeing, ok := utils.WiFiShift(test, rejected)
+			if !ok {
+				test.Rejected, test.RejectedMetric = true, metric
+				if _, err := col.InsertOne(ctx, test); err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save test"})
+					return
+				}
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "result is far outside what others measured here", "metric": metric})
+				return
+			}
+			shift = agreeing
+		}
+
+		if _, err := col.InsertOne(ctx, test); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not save test"})
+			return
+		}
+		if shift != nil {
+			if err := utils.AcceptWiFiShift(ctx, cfg, test, shift); err != nil {
+				log.Printf("⚠️ could not apply wifi change at hub %s: %v", hubID.Hex(), err)
+			}
+		}
+
+		stats, err := utils.RefreshHubWiFi(ctx, cfg, hubID)
+		if err != nil {
+			log.Printf("⚠️ could not refresh wifi stats of hub %s: %v", hubID.Hex(), err)
+		}
+
+		c.JSON(http.StatusCreated, gin.H{
+			"test": test,
+			"wifi": stats,
+		})
+	}
+}
+
+// ---------------- LIST WIFI TESTS ----------------
+// ListWiFiTests returns the tests behind the hub's current medians
+func ListWiFiTests(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hubID, err := primitive.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hub id"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		tests, err := utils.RecentWiFiTests(ctx, cfg, hubID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not fetch tests"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"tests": tests,
+			"wifi":  utils.WiFiStatsFor(tests, time.Now()),
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	config "github.com/phillip/contribution-tracker-go/config"
 	routes "github.com/phillip/contribution-tracker-go/routes"
+	utils "github.com/phillip/contribution-tracker-go/utils"
 )
 
 func main() {
@@ -25,6 +26,26 @@ func main() {
         log.Fatalf("config load error: %v", err)
     }
 
+    // Outgoing email transport
+    if _, err := utils.InitMailer(cfg); err != nil {
+        log.Fatalf("mailer init error: %v", err)
+    }
+
+    // Blob storage for uploads
+    if _, err := utils.InitStorage(cfg); err != nil {
+        log.Fatalf("storage init error: %v", err)
+    }
+
+    // Review screening pipeline
+    if err := utils.InitScreening(cfg); err != nil {
+        log.Fatalf("screening init error: %v", err)
+    }
+
+    // Offline reverse geocoding
+    if err := utils.InitGeocoder(cfg); err != nil {
+        log.Fatalf("geocoder init error: %v", err)
+    }
+
     // ✅ Connect to MongoDB first
     client := config.ConnectDB()
     if client == nil {
@@ -34,6 +55,15 @@ func main() {
 
     // ✅ Now ensure indexes
     config.EnsureAllIndexes(client, cfg.DBName)
+    utils.MigrateLegacyImages(cfg)
+    utils.BackfillHubPhotos(cfg)
+    utils.BackfillFavoriteCounts(cfg)
+    utils.FailInterruptedImports(cfg)
+    utils.BackfillHubAddresses(cfg)
+    utils.StartAssetReconciler(cfg)
+    utils.StartCheckInSweeper(cfg)
+    utils.StartPopularTimesJob(cfg)
+    utils.StartWiFiRefreshJob(cfg)
 
 	// Gin router
 	r := gin.Default()
@@ -1,12 +1,17 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	config "github.com/phillip/contribution-tracker-go/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
@@ -34,8 +39,31 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
             c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid user_id in token"})
             return
         }
-        // Set user_id in Gin context
+        objID, err := primitive.ObjectIDFromHex(userID)
+        if err != nil {
+            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid user_id in token"})
+            return
+        }
+
+        // The role claim is informational only: read the current role so
+        // promotions and demotions apply without waiting for a new token
+        ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+        var user struct {
+            Role string `bson:"role"`
+        }
+        err = cfg.MongoClient.Database(cfg.DBName).Collection("users").FindOne(ctx,
+            bson.M{"_id": objID},
+            options.FindOne().SetProjection(bson.M{"role": 1}),
+        ).Decode(&user)
+        if err != nil {
+            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+            return
+        }
+
+        // Set user_id and role in Gin context
         c.Set("user_id", userID)
+        c.Set("role", user.Role)
         c.Next()
     }
 }
\ No newline at end of file
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	utils "github.com/phillip/contribution-tracker-go/utils"
+)
+
+// BodyLimit caps the request body at maxBytes. Multipart forms are parsed
+// here so an oversized upload is rejected before any handler touches it.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tooLarge := func() {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": "request too large",
+				"details": utils.UploadErrors{{
+					Index:  -1,
+					Code:   utils.UploadRequestTooLarge,
+					Reason: fmt.Sprintf("request body exceeds %d MB", maxBytes>>20),
+				}},
+			})
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			tooLarge()
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+
+		if strings.HasPrefix(c.ContentType(), "multipart/") {
+			if _, err := c.MultipartForm(); err != nil {
+				var mbe *http.MaxBytesError
+				if errors.As(err, &mbe) {
+					tooLarge()
+					return
+				}
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid form data"})
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
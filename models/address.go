@@ -0,0 +1,10 @@
+package models
+
+// Address is a hub's place in the boundary hierarchy, derived from its
+// coordinates. Levels the boundaries don't cover are left empty.
+type Address struct {
+	Country       string `bson:"country,omitempty" json:"country,omitempty"`
+	CountryCode   string `bson:"country_code,omitempty" json:"country_code,omitempty"` // ISO 3166-1 alpha-2
+	City          string `bson:"city,omitempty" json:"city,omitempty"`
+	Neighbourhood string `bson:"neighbourhood,omitempty" json:"neighbourhood,omitempty"`
+}
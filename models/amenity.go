@@ -0,0 +1,47 @@
+package models
+
+// Amenities a hub can list. Clients send and filter by these IDs.
+var Amenities = []string{
+	"wifi",
+	"power_outlets",
+	"quiet_zone",
+	"meeting_rooms",
+	"phone_booths",
+	"printing",
+	"coffee",
+	"food",
+	"air_conditioning",
+	"outdoor_seating",
+	"parking",
+	"accessible",
+	"lockers",
+	"showers",
+	"open_24h",
+}
+
+// Price bands, from free to expensive
+const (
+	PriceBandFree   = "free"
+	PriceBandLow    = "low"
+	PriceBandMedium = "medium"
+	PriceBandHigh   = "high"
+)
+
+// IsValidAmenity reports whether a is a known amenity ID
+func IsValidAmenity(a string) bool {
+	for _, known := range Amenities {
+		if a == known {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidPriceBand reports whether p is a known price band
+func IsValidPriceBand(p string) bool {
+	switch p {
+	case PriceBandFree, PriceBandLow, PriceBandMedium, PriceBandHigh:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Storage providers an Asset can live in
+const (
+	ProviderCloudinary = "cloudinary"
+	ProviderLocal      = "local"
+	ProviderS3         = "s3"
+
+	// ProviderLegacy marks a bare URL stored before assets existed
+	ProviderLegacy = "legacy"
+)
+
+// Asset is a stored file. Provider + Key identify the object for deletion,
+// URL is what clients load. Hubs and events embed copies of these; the
+// "assets" collection keeps the canonical record of everything uploaded.
+//
+// Processed images are stored only as resized variants; Key and URL then
+// point at the "full" JPEG.
+type Asset struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Provider    string             `bson:"provider" json:"provider"`
+	Key         string             `bson:"key" json:"-"`
+	URL         string             `bson:"url" json:"url"`
+	ContentType string             `bson:"content_type,omitempty" json:"content_type,omitempty"`
+	Size        int64              `bson:"size,omitempty" json:"size,omitempty"`
+	Width       int                `bson:"width,omitempty" json:"width,omitempty"`
+	Height      int                `bson:"height,omitempty" json:"height,omitempty"`
+	BlurHash    string             `bson:"blurhash,omitempty" json:"blurhash,omitempty"`
+	Variants    []AssetVariant     `bson:"variants,omitempty" json:"variants,omitempty"`
+	UploadedBy  primitive.ObjectID `bson:"uploaded_by,omitempty" json:"uploaded_by,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// AssetVariant is one stored rendition of an image, e.g. the WebP thumbnail
+type AssetVariant struct {
+	Name        string `bson:"name" json:"name"`     // thumb, card, full
+	Format      string `bson:"format" json:"format"` // jpeg, webp
+	Key         string `bson:"key" json:"-"`
+	URL         string `bson:"url" json:"url"`
+	ContentType string `bson:"content_type" json:"content_type"`
+	Width       int    `bson:"width" json:"width"`
+	Height      int    `bson:"height" json:"height"`
+	Size        int64  `bson:"size" json:"size"`
+}
+
+// Keys lists every stored object belonging to the asset
+func (a Asset) Keys() []string {
+	keys := []string{}
+	seen := map[string]bool{}
+	for _, k := range append([]string{a.Key}, variantKeys(a.Variants)...) {
+		if k != "" && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func variantKeys(vs []AssetVariant) []string {
+	keys := make([]string, 0, len(vs))
+	for _, v := range vs {
+		keys = append(keys, v.Key)
+	}
+	return keys
+}
+
+// UnmarshalBSONValue accepts both asset documents and the bare URL strings
+// older hubs and events were saved with.
+func (a *Asset) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	switch t {
+	case bsontype.String:
+		var url string
+		if err := bson.UnmarshalValue(t, data, &url); err != nil {
+			return err
+		}
+		*a = Asset{Provider: ProviderLegacy, URL: url}
+		return nil
+	case bsontype.EmbeddedDocument:
+		type plain Asset
+		var p plain
+		if err := bson.Unmarshal(data, &p); err != nil {
+			return err
+		}
+		*a = Asset(p)
+		return nil
+	default:
+		return fmt.Errorf("cannot decode %s into Asset", t)
+	}
+}
+
+// Per-file upload outcomes
+const (
+	UploadStatusUploaded   = "uploaded"
+	UploadStatusFailed     = "failed"
+	UploadStatusRolledBack = "rolled_back"
+	UploadStatusSkipped    = "skipped"
+)
+
+// UploadResult reports what happened to one file of a multi-file upload
+type UploadResult struct {
+	File    string `json:"file"`
+	Index   int    `json:"index"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+	AssetID string `json:"asset_id,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
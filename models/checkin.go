@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CheckIn records a user being at a hub. Crowd, Noise and WiFi are optional
+// 1–5 reports (1 = empty/quiet/unusable, 5 = packed/loud/excellent).
+type CheckIn struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	HubID          primitive.ObjectID `bson:"hub_id" json:"hub_id"`
+	UserID         primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Active         bool               `bson:"active" json:"active"`
+	Coordinates    *Coordinates       `bson:"coordinates,omitempty" json:"coordinates,omitempty"`
+	DistanceMeters *float64           `bson:"distance_m,omitempty" json:"distance_meters,omitempty"`
+	Verified       bool               `bson:"verified" json:"verified"` // within range of the hub
+	Crowd          *int               `bson:"crowd,omitempty" json:"crowd,omitempty"`
+	Noise          *int               `bson:"noise,omitempty" json:"noise,omitempty"`
+	WiFi           *int               `bson:"wifi,omitempty" json:"wifi,omitempty"`
+	ReportedAt     *time.Time         `bson:"reported_at,omitempty" json:"reported_at,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt      time.Time          `bson:"expires_at" json:"expires_at"`
+	CheckedOutAt   *time.Time         `bson:"checked_out_at,omitempty" json:"checked_out_at,omitempty"`
+	AutoCheckedOut bool               `bson:"auto_checked_out,omitempty" json:"auto_checked_out,omitempty"`
+}
+
+// LiveReport is one recent crowd/noise/wifi report as shown on a hub
+type LiveReport struct {
+	Crowd      *int      `json:"crowd,omitempty"`
+	Noise      *int      `json:"noise,omitempty"`
+	WiFi       *int      `json:"wifi,omitempty"`
+	Verified   bool      `json:"verified"`
+	ReportedAt time.Time `json:"reported_at"`
+}
+
+// LiveStatus is how busy a hub is right now. Levels are age-weighted
+// averages of recent reports, nil when nobody reported.
+type LiveStatus struct {
+	ActiveCheckIns int64        `json:"active_checkins"`
+	Crowd          *float64     `json:"crowd,omitempty"`
+	Noise          *float64     `json:"noise,omitempty"`
+	WiFi           *float64     `json:"wifi,omitempty"`
+	Reports        []LiveReport `json:"recent_reports"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Collection visibility
+const (
+	CollectionPrivate  = "private"  // owner and collaborators only
+	CollectionUnlisted = "unlisted" // anyone with the share link
+	CollectionPublic   = "public"   // listed for everyone
+)
+
+// Collection is a user-curated, ordered list of hubs. Items are kept in
+// display order.
+type Collection struct {
+	ID            primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	OwnerID       primitive.ObjectID   `bson:"owner_id" json:"owner_id"`
+	Title         string               `bson:"title" json:"title"`
+	Description   string               `bson:"description,omitempty" json:"description,omitempty"`
+	Visibility    string               `bson:"visibility" json:"visibility"`
+	Slug          string               `bson:"slug" json:"slug,omitempty"` // unguessable share token
+	Collaborators []primitive.ObjectID `bson:"collaborators" json:"collaborators"`
+	Invited       []primitive.ObjectID `bson:"invited" json:"invited,omitempty"` // pending collaborator invites
+	Items         []CollectionItem     `bson:"items" json:"items"`
+	CreatedAt     time.Time            `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time            `bson:"updated_at" json:"updated_at"`
+
+	// enriched
+	OwnerName string `bson:"-" json:"owner_name,omitempty"`
+}
+
+// CollectionItem is a hub in a collection with the curator's note
+type CollectionItem struct {
+	HubID   primitive.ObjectID `bson:"hub_id" json:"hub_id"`
+	Note    string             `bson:"note,omitempty" json:"note,omitempty"`
+	AddedBy primitive.ObjectID `bson:"added_by" json:"added_by"`
+	AddedAt time.Time          `bson:"added_at" json:"added_at"`
+
+	// enriched; nil when the hub is no longer visible
+	Hub *Hub `bson:"-" json:"hub,omitempty"`
+}
+
+// IsValidCollectionVisibility reports whether v is a known visibility
+func IsValidCollectionVisibility(v string) bool {
+	switch v {
+	case CollectionPrivate, CollectionUnlisted, CollectionPublic:
+		return true
+	}
+	return false
+}
+
+// IsEditor reports whether the user may change the collection's items
+func (c Collection) IsEditor(userID primitive.ObjectID) bool {
+	if c.OwnerID == userID {
+		return true
+	}
+	for _, id := range c.Collaborators {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInvited reports whether the user has a pending invite
+func (c Collection) IsInvited(userID primitive.ObjectID) bool {
+	for _, id := range c.Invited {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -15,7 +15,10 @@ type Event struct {
 	TargetAmount float64            `bson:"target_amount,omitempty" json:"target_amount,omitempty"`
 	Deadline     *time.Time         `bson:"deadline,omitempty" json:"deadline,omitempty"`
 	Status       string             `bson:"status" json:"status"` // ACTIVE, CLOSED, ARCHIVED
-	Images       []string            `bson:"images" json:"images"`
+	Images       []Asset            `bson:"images" json:"images"`
 	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
+
+	// Enriched fields
+	Uploads []UploadResult `json:"uploads,omitempty" bson:"-"`
 }
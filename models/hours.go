@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OpeningPeriod is one opening interval on a weekday, in the hub's local
+// time. Close before Open means the period runs past midnight.
+type OpeningPeriod struct {
+	Day   time.Weekday `bson:"day" json:"day"`     // 0 = Sunday
+	Open  string       `bson:"open" json:"open"`   // HH:MM
+	Close string       `bson:"close" json:"close"` // HH:MM
+}
+
+var clockPattern = regexp.MustCompile(`^([01]\d|2[0-3]):[0-5]\d$`)
+
+// Validate checks the weekday and HH:MM times
+func (p OpeningPeriod) Validate() error {
+	if p.Day < time.Sunday || p.Day > time.Saturday {
+		return fmt.Errorf("day must be 0 (Sunday) to 6 (Saturday)")
+	}
+	if !clockPattern.MatchString(p.Open) || !clockPattern.MatchString(p.Close) {
+		return fmt.Errorf("open and close must be HH:MM")
+	}
+	return nil
+}
+
+// FormatOpeningHours renders periods as "Mon 08:00-18:00; Tue 08:00-18:00",
+// Monday first, for exports
+func FormatOpeningHours(periods []OpeningPeriod) string {
+	sorted := append([]OpeningPeriod(nil), periods...)
+	mondayFirst := func(d time.Weekday) int { return (int(d) + 6) % 7 }
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Day != sorted[j].Day {
+			return mondayFirst(sorted[i].Day) < mondayFirst(sorted[j].Day)
+		}
+		return sorted[i].Open < sorted[j].Open
+	})
+	parts := make([]string, 0, len(sorted))
+	for _, p := range sorted {
+		parts = append(parts, fmt.Sprintf("%s %s-%s", p.Day.String()[:3], p.Open, p.Close))
+	}
+	return strings.Join(parts, "; ")
+}
+
+var weekdayAbbrev = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseOpeningHours reads the FormatOpeningHours format back. A period may
+// cover a run of days, e.g. "Mon-Fri 08:00-18:00; Sat 10:00-14:00".
+func ParseOpeningHours(s string) ([]OpeningPeriod, error) {
+	periods := []OpeningPeriod{}
+	for _, part := range strings.Split(s, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		fields := strings.Fields(part)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("opening hours %q must look like \"Mon-Fri 08:00-18:00\"", part)
+		}
+		days := strings.SplitN(strings.ToLower(fields[0]), "-", 2)
+		first, ok1 := weekdayAbbrev[days[0]]
+		last, ok2 := first, true
+		if len(days) == 2 {
+			last, ok2 = weekdayAbbrev[days[1]]
+		}
+		times := strings.SplitN(fields[1], "-", 2)
+		if !ok1 || !ok2 || len(times) != 2 {
+			return nil, fmt.Errorf("opening hours %q must look like \"Mon-Fri 08:00-18:00\"", part)
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			p := OpeningPeriod{Day: d, Open: times[0], Close: times[1]}
+			if err := p.Validate(); err != nil {
+				return nil, fmt.Errorf("opening hours %q: %v", part, err)
+			}
+			periods = append(periods, p)
+			if d == last {
+				break
+			}
+		}
+	}
+	return periods, nil
+}
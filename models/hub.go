@@ -19,14 +19,37 @@ type Hub struct {
 	Description  string             `bson:"description,omitempty" json:"description,omitempty"`
 	Coordinates  Coordinates        `bson:"coordinates,omitempty" json:"coordinates,omitempty"`
 	LocationName string             `bson:"location,omitempty" json:"location_name,omitempty"`
+	TimeZone     string             `bson:"timezone,omitempty" json:"timezone,omitempty"` // IANA name, e.g. Africa/Nairobi
+	Address      *Address           `bson:"address,omitempty" json:"address,omitempty"` // derived from coordinates
+	LocationMismatch bool           `bson:"location_mismatch,omitempty" json:"location_mismatch,omitempty"` // LocationName names a place the coordinates are not in
+	Neighbourhood string            `bson:"neighbourhood,omitempty" json:"neighbourhood,omitempty"` // taxonomy slug
+	NeighbourhoodMismatch bool      `bson:"neighbourhood_mismatch,omitempty" json:"neighbourhood_mismatch,omitempty"` // Neighbourhood is a known place the coordinates are not in
+	Categories   []string           `bson:"categories,omitempty" json:"categories,omitempty"`       // taxonomy slugs
+	Tags         []string           `bson:"tags,omitempty" json:"tags,omitempty"`                   // normalized user tags
+	Amenities    []string           `bson:"amenities,omitempty" json:"amenities,omitempty"` // IDs from models.Amenities
+	PriceBand    string             `bson:"price_band,omitempty" json:"price_band,omitempty"`
+	Hours        []OpeningPeriod    `bson:"hours,omitempty" json:"hours,omitempty"` // opening hours, local time
+	Status       string             `bson:"status,omitempty" json:"status,omitempty"` // HubStatus*; empty is open
 	Rating       float64            `bson:"target_amount,omitempty" json:"rating,omitempty"`
-	Images       []string           `bson:"images" json:"images"`
+	Images       []Asset            `bson:"images" json:"images"` // approved gallery, cover first
+	WiFi         *WiFiStats         `bson:"wifi,omitempty" json:"wifi,omitempty"` // medians of recent speed tests
+	FavoritesCount int              `bson:"favorites_count" json:"favorites_count"`
+	CoverPhotoID *primitive.ObjectID `bson:"cover_photo_id,omitempty" json:"cover_photo_id,omitempty"`
+	MergedInto   *primitive.ObjectID `bson:"merged_into,omitempty" json:"merged_into,omitempty"` // set on hubs merged away
+	MergedAt     *time.Time         `bson:"merged_at,omitempty" json:"merged_at,omitempty"`
+	Hidden       bool               `bson:"hidden,omitempty" json:"hidden,omitempty"` // hidden by a moderator
+	Verified     bool               `bson:"verified,omitempty" json:"verified,omitempty"` // UserID is the business, per an approved claim
+	VerifiedAt   *time.Time         `bson:"verified_at,omitempty" json:"verified_at,omitempty"`
+	VerifiedClaimID *primitive.ObjectID `bson:"verified_claim_id,omitempty" json:"verified_claim_id,omitempty"`
 	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
 
 	// Enriched fields
 	IsFavorite bool                     `json:"is_favorite,omitempty" bson:"-"`
 	Reviews    []ReviewResponse         `json:"reviews,omitempty" bson:"-"`
+	Uploads    []UploadResult           `json:"uploads,omitempty" bson:"-"`
+	TextScore  float64                  `json:"-" bson:"text_score,omitempty"` // projected by text search, never stored
+	Relevance  float64                  `json:"relevance,omitempty" bson:"-"`
 }
 
 
@@ -37,10 +60,28 @@ type Review struct {
 	HubID     primitive.ObjectID `bson:"hub_id" json:"hub_id"`
 	Rating    int                `bson:"rating" json:"rating"` // 1–5
 	Comment   string             `bson:"comment,omitempty" json:"comment,omitempty"`
+	Hidden    bool               `bson:"hidden,omitempty" json:"hidden,omitempty"` // hidden by a moderator
+	Status    string             `bson:"status,omitempty" json:"status,omitempty"`
+	Flags     []ScreeningFlag    `bson:"flags,omitempty" json:"flags,omitempty"`
+	TextHash  string             `bson:"text_hash,omitempty" json:"-"` // normalized comment, for copy detection
+	OwnerReply *OwnerReply       `bson:"owner_reply,omitempty" json:"owner_reply,omitempty"`
 	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
+// Review publication states; reviews without a status are published
+const (
+	ReviewStatusPublished = "published"
+	ReviewStatusPending   = "pending"
+)
+
+// ScreeningFlag is one reason automatic screening held a review
+type ScreeningFlag struct {
+	Check  string `bson:"check" json:"check"`   // screener that raised it
+	Reason string `bson:"reason" json:"reason"` // report reason code, e.g. spam
+	Detail string `bson:"detail,omitempty" json:"detail,omitempty"`
+}
+
 // --- Favorite ---
 type Favorite struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
@@ -57,5 +98,6 @@ type ReviewResponse struct {
 	HubID     primitive.ObjectID `json:"hub_id"`
 	Rating    int                `json:"rating"`
 	Comment   string             `json:"comment"`
+	OwnerReply *OwnerReply       `json:"owner_reply,omitempty"`
 	CreatedAt time.Time          `json:"created_at"`
 }
\ No newline at end of file
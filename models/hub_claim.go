@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Hub claim states
+const (
+	ClaimStatusPending   = "pending"
+	ClaimStatusApproved  = "approved"
+	ClaimStatusRejected  = "rejected"
+	ClaimStatusWithdrawn = "withdrawn"
+	ClaimStatusRevoked   = "revoked"
+)
+
+// Roles a claimant can hold at the business
+const (
+	ClaimRoleOwner   = "owner"
+	ClaimRoleManager = "manager"
+)
+
+// Claim audit actions
+const (
+	ClaimActionSubmitted  = "submitted"
+	ClaimActionApproved   = "approved"
+	ClaimActionRejected   = "rejected"
+	ClaimActionWithdrawn  = "withdrawn"
+	ClaimActionRevoked    = "revoked"
+	ClaimActionSuperseded = "superseded" // revoked because another claim was approved
+)
+
+// HubClaim is a business asking to own a hub's listing. An approved claim
+// transfers Hub.UserID to the claimant and marks the hub verified; revoking
+// it hands the hub back to PreviousOwnerID.
+type HubClaim struct {
+	ID                 primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	HubID              primitive.ObjectID  `bson:"hub_id" json:"hub_id"`
+	UserID             primitive.ObjectID  `bson:"user_id" json:"user_id"` // claimant
+	BusinessName       string              `bson:"business_name" json:"business_name"`
+	Role               string              `bson:"role" json:"role"`
+	ContactEmail       string              `bson:"contact_email,omitempty" json:"contact_email,omitempty"`
+	ContactPhone       string              `bson:"contact_phone,omitempty" json:"contact_phone,omitempty"`
+	Website            string              `bson:"website,omitempty" json:"website,omitempty"`
+	RegistrationNumber string              `bson:"registration_number,omitempty" json:"registration_number,omitempty"`
+	Note               string              `bson:"note,omitempty" json:"note,omitempty"`
+	Evidence           []Asset             `bson:"evidence" json:"evidence"` // e.g. business permit, storefront photos
+	Status             string              `bson:"status" json:"status"`
+	PreviousOwnerID    *primitive.ObjectID `bson:"previous_owner_id,omitempty" json:"previous_owner_id,omitempty"` // set on approval
+	ReviewedBy         *primitive.ObjectID `bson:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	ReviewedAt         *time.Time          `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+	DecisionNote       string              `bson:"decision_note,omitempty" json:"decision_note,omitempty"`
+	History            []ClaimEvent        `bson:"history" json:"history"` // audit trail, oldest first
+	CreatedAt          time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt          time.Time           `bson:"updated_at" json:"updated_at"`
+
+	// Enriched fields
+	HubTitle     string `bson:"-" json:"hub_title,omitempty"`
+	ClaimantName string `bson:"-" json:"claimant_name,omitempty"`
+}
+
+// ClaimEvent is one step in a claim's audit trail
+type ClaimEvent struct {
+	Action  string             `bson:"action" json:"action"`
+	ActorID primitive.ObjectID `bson:"actor_id" json:"actor_id"`
+	Note    string             `bson:"note,omitempty" json:"note,omitempty"`
+	At      time.Time          `bson:"at" json:"at"`
+}
+
+// OwnerReply is a verified owner's public answer to a review
+type OwnerReply struct {
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Text      string             `bson:"text" json:"text"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
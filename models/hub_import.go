@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Hub import job states
+const (
+	ImportStatusQueued    = "queued"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// Outcomes of one imported row. Dry runs report would-be hubs as valid.
+const (
+	ImportRowCreated = "created"
+	ImportRowValid   = "valid"
+	ImportRowSkipped = "skipped"
+	ImportRowFailed  = "failed"
+)
+
+// HubImport is one bulk import of hubs from a CSV or GeoJSON file. Small
+// files are processed in the request; large ones in the background, with
+// progress kept here for polling.
+type HubImport struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatedBy  primitive.ObjectID `bson:"created_by" json:"created_by"`
+	Filename   string             `bson:"filename" json:"filename"`
+	Format     string             `bson:"format" json:"format"` // csv or geojson
+	DryRun     bool               `bson:"dry_run" json:"dry_run"`
+	Status     string             `bson:"status" json:"status"`
+	Error      string             `bson:"error,omitempty" json:"error,omitempty"` // why the whole job failed
+	Total      int                `bson:"total" json:"total"`
+	Processed  int                `bson:"processed" json:"processed"`
+	Created    int                `bson:"created" json:"created"` // or would be, in a dry run
+	Skipped    int                `bson:"skipped" json:"skipped"`
+	Failed     int                `bson:"failed" json:"failed"`
+	Rows       []ImportRowResult  `bson:"rows" json:"rows"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	StartedAt  *time.Time         `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	FinishedAt *time.Time         `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+}
+
+// ImportRowResult is the outcome of one row; Row is its 1-based line in a
+// CSV (after the header) or its index in a GeoJSON feature list
+type ImportRowResult struct {
+	Row          int                  `bson:"row" json:"row"`
+	Title        string               `bson:"title,omitempty" json:"title,omitempty"`
+	Status       string               `bson:"status" json:"status"`
+	HubID        *primitive.ObjectID  `bson:"hub_id,omitempty" json:"hub_id,omitempty"`
+	Errors       []string             `bson:"errors,omitempty" json:"errors,omitempty"`
+	DuplicateOf  []primitive.ObjectID `bson:"duplicate_of,omitempty" json:"duplicate_of,omitempty"`   // existing hubs it matched
+	DuplicateRow int                  `bson:"duplicate_row,omitempty" json:"duplicate_row,omitempty"` // earlier row in the same file it matched
+}
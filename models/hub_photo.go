@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Hub photo categories
+const (
+	PhotoCategoryInterior = "interior"
+	PhotoCategoryOutlets  = "outlets"
+	PhotoCategoryMenu     = "menu"
+	PhotoCategoryExterior = "exterior"
+)
+
+// Hub photo moderation states
+const (
+	PhotoStatusApproved = "approved"
+	PhotoStatusPending  = "pending"
+	PhotoStatusRejected = "rejected"
+)
+
+// HubPhoto is one image in a hub's gallery. Approved photos are mirrored,
+// in gallery order with the cover first, into Hub.Images.
+type HubPhoto struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	HubID      primitive.ObjectID  `bson:"hub_id" json:"hub_id"`
+	Asset      Asset               `bson:"asset" json:"asset"`
+	Caption    string              `bson:"caption,omitempty" json:"caption,omitempty"`
+	Category   string              `bson:"category,omitempty" json:"category,omitempty"`
+	UploadedBy primitive.ObjectID  `bson:"uploaded_by" json:"uploaded_by"`
+	Status     string              `bson:"status" json:"status"`
+	Position   int                 `bson:"position" json:"position"`
+	Hidden     bool                `bson:"hidden,omitempty" json:"hidden,omitempty"`     // hidden by a moderator
+	Official   bool                `bson:"official,omitempty" json:"official,omitempty"` // from the hub's verified owner
+	ReviewedBy *primitive.ObjectID `bson:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time          `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time           `bson:"updated_at" json:"updated_at"`
+
+	// Enriched fields
+	UploaderName string `bson:"-" json:"uploader_name,omitempty"`
+	IsCover      bool   `bson:"-" json:"is_cover"`
+}
+
+// IsValidPhotoCategory reports whether c is a known category (empty is allowed)
+func IsValidPhotoCategory(c string) bool {
+	switch c {
+	case "", PhotoCategoryInterior, PhotoCategoryOutlets, PhotoCategoryMenu, PhotoCategoryExterior:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Edit suggestion states
+const (
+	EditStatusPending  = "pending"
+	EditStatusAccepted = "accepted"
+	EditStatusRejected = "rejected"
+)
+
+// Revision sources
+const (
+	RevisionSourceCreate     = "create"
+	RevisionSourceBaseline   = "baseline" // state of a hub that predates history
+	RevisionSourceOwnerEdit  = "owner_edit"
+	RevisionSourceSuggestion = "suggestion"
+	RevisionSourceRevert     = "revert"
+	RevisionSourceImport     = "import"
+)
+
+// Hub states, so stale listings can be marked closed rather than deleted
+const (
+	HubStatusOpen              = "open"
+	HubStatusClosed            = "temporarily_closed"
+	HubStatusPermanentlyClosed = "permanently_closed"
+)
+
+// IsValidHubStatus reports whether s is a known hub state
+func IsValidHubStatus(s string) bool {
+	switch s {
+	case HubStatusOpen, HubStatusClosed, HubStatusPermanentlyClosed:
+		return true
+	}
+	return false
+}
+
+// hubStatus reads an unset status, as on hubs and snapshots older than
+// statuses, as open
+func hubStatus(s string) string {
+	if s == "" {
+		return HubStatusOpen
+	}
+	return s
+}
+
+// HubFields are the community-editable fields of a hub
+type HubFields struct {
+	Title        string          `bson:"title" json:"title"`
+	Description  string          `bson:"description" json:"description"`
+	Coordinates  Coordinates     `bson:"coordinates" json:"coordinates"`
+	LocationName string          `bson:"location" json:"location_name"`
+	Hours        []OpeningPeriod `bson:"hours" json:"hours"`
+	Status       string          `bson:"status" json:"status"`
+}
+
+// FieldChange is one changed field; Field uses the json name. Opening
+// hours are compared and stored as their FormatOpeningHours text.
+type FieldChange struct {
+	Field string      `bson:"field" json:"field"`
+	From  interface{} `bson:"from" json:"from"`
+	To    interface{} `bson:"to" json:"to"`
+}
+
+// HubEdit is a field-level change proposed by any user
+type HubEdit struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	HubID      primitive.ObjectID  `bson:"hub_id" json:"hub_id"`
+	UserID     primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	Changes    []FieldChange       `bson:"changes" json:"changes"`
+	Note       string              `bson:"note,omitempty" json:"note,omitempty"`
+	Status     string              `bson:"status" json:"status"`
+	ReviewedBy *primitive.ObjectID `bson:"reviewed_by,omitempty" json:"reviewed_by,omitempty"`
+	ReviewNote string              `bson:"review_note,omitempty" json:"review_note,omitempty"`
+	ReviewedAt *time.Time          `bson:"reviewed_at,omitempty" json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at"`
+
+	// Enriched fields
+	UserName string `bson:"-" json:"user_name,omitempty"`
+}
+
+// HubRevision records an applied change and the resulting field values
+type HubRevision struct {
+	ID        primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	HubID     primitive.ObjectID  `bson:"hub_id" json:"hub_id"`
+	Number    int                 `bson:"number" json:"number"`
+	AuthorID  primitive.ObjectID  `bson:"author_id" json:"author_id"`
+	Source    string              `bson:"source" json:"source"`
+	EditID    *primitive.ObjectID `bson:"edit_id,omitempty" json:"edit_id,omitempty"`
+	RevertOf  *primitive.ObjectID `bson:"revert_of,omitempty" json:"revert_of,omitempty"`
+	Changes   []FieldChange       `bson:"changes" json:"changes"`
+	Snapshot  HubFields           `bson:"snapshot" json:"snapshot"`
+	CreatedAt time.Time           `bson:"created_at" json:"created_at"`
+
+	// Enriched fields
+	AuthorName string `bson:"-" json:"author_name,omitempty"`
+}
+
+// Fields returns the editable fields of the hub
+func (h Hub) Fields() HubFields {
+	return HubFields{
+		Title:        h.Title,
+		Description:  h.Description,
+		Coordinates:  h.Coordinates,
+		LocationName: h.LocationName,
+		Hours:        h.Hours,
+		Status:       hubStatus(h.Status),
+	}
+}
+
+// Diff lists the fields that differ between f and next
+func (f HubFields) Diff(next HubFields) []FieldChange {
+	changes := []FieldChange{}
+	if f.Title != next.Title {
+		changes = append(changes, FieldChange{Field: "title", From: f.Title, To: next.Title})
+	}
+	if f.Description != next.Description {
+		changes = append(changes, FieldChange{Field: "description", From: f.Description, To: next.Description})
+	}
+	if f.Coordinates.Lat != next.Coordinates.Lat {
+		changes = append(changes, FieldChange{Field: "lat", From: f.Coordinates.Lat, To: next.Coordinates.Lat})
+	}
+	if f.Coordinates.Lng != next.Coordinates.Lng {
+		changes = append(changes, FieldChange{Field: "lng", From: f.Coordinates.Lng, To: next.Coordinates.Lng})
+	}
+	if f.LocationName != next.LocationName {
+		changes = append(changes, FieldChange{Field: "location_name", From: f.LocationName, To: next.LocationName})
+	}
+	if from, to := FormatOpeningHours(f.Hours), FormatOpeningHours(next.Hours); from != to {
+		changes = append(changes, FieldChange{Field: "opening_hours", From: from, To: to})
+	}
+	if from, to := hubStatus(f.Status), hubStatus(next.Status); from != to {
+		changes = append(changes, FieldChange{Field: "status", From: from, To: to})
+	}
+	return changes
+}
+
+// Apply returns f with the To values of changes set
+func (f HubFields) Apply(changes []FieldChange) HubFields {
+	for _, ch := range changes {
+		switch ch.Field {
+		case "title":
+			f.Title, _ = ch.To.(string)
+		case "description":
+			f.Description, _ = ch.To.(string)
+		case "lat":
+			f.Coordinates.Lat, _ = ch.To.(float64)
+		case "lng":
+			f.Coordinates.Lng, _ = ch.To.(float64)
+		case "location_name":
+			f.LocationName, _ = ch.To.(string)
+		case "opening_hours":
+			text, _ := ch.To.(string)
+			if hours, err := ParseOpeningHours(text); err == nil {
+				f.Hours = hours
+			}
+		case "status":
+			f.Status, _ = ch.To.(string)
+		}
+	}
+	return f
+}
+
+// Conflicts lists changes whose From no longer matches f, i.e. the field
+// was edited after the suggestion was made
+func (f HubFields) Conflicts(changes []FieldChange) []string {
+	current := map[string]interface{}{
+		"title":         f.Title,
+		"description":   f.Description,
+		"lat":           f.Coordinates.Lat,
+		"lng":           f.Coordinates.Lng,
+		"location_name": f.LocationName,
+		"opening_hours": FormatOpeningHours(f.Hours),
+		"status":        hubStatus(f.Status),
+	}
+	stale := []string{}
+	for _, ch := range changes {
+		if current[ch.Field] != ch.From {
+			stale = append(stale, ch.Field)
+		}
+	}
+	return stale
+}
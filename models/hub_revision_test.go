@@ -0,0 +1,50 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHubFieldsHoursAndStatus(t *testing.T) {
+	weekdays := []OpeningPeriod{{Day: time.Monday, Open: "08:00", Close: "18:00"}}
+	current := Hub{Title: "Java House", Hours: weekdays}.Fields()
+	if current.Status != HubStatusOpen {
+		t.Fatalf("Fields().Status = %q, want %q", current.Status, HubStatusOpen)
+	}
+
+	proposed := current
+	proposed.Hours = append([]OpeningPeriod{{Day: time.Saturday, Open: "10:00", Close: "14:00"}}, weekdays...)
+	proposed.Status = HubStatusPermanentlyClosed
+	changes := current.Diff(proposed)
+
+	want := []FieldChange{
+		{Field: "opening_hours", From: "Mon 08:00-18:00", To: "Mon 08:00-18:00; Sat 10:00-14:00"},
+		{Field: "status", From: HubStatusOpen, To: HubStatusPermanentlyClosed},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("Diff() = %+v, want %+v", changes, want)
+	}
+
+	applied := current.Apply(changes)
+	if len(applied.Diff(proposed)) != 0 || applied.Status != HubStatusPermanentlyClosed {
+		t.Errorf("Apply() = %+v, want %+v", applied, proposed)
+	}
+
+	if stale := current.Conflicts(changes); len(stale) != 0 {
+		t.Errorf("Conflicts() on the unchanged hub = %v, want none", stale)
+	}
+	edited := current
+	edited.Hours = nil
+	if stale := edited.Conflicts(changes); !reflect.DeepEqual(stale, []string{"opening_hours"}) {
+		t.Errorf("Conflicts() after hours changed = %v, want [opening_hours]", stale)
+	}
+}
+
+func TestHubFieldsUnsetStatus(t *testing.T) {
+	// snapshots from before statuses existed read as open
+	old := HubFields{Title: "Java House"}
+	if changes := old.Diff(Hub{Title: "Java House"}.Fields()); len(changes) != 0 {
+		t.Errorf("Diff() = %+v, want none", changes)
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PopularTimes is a hub's typical busyness by weekday (0 = Sunday) and hour
+// in the hub's local time. Occupancy is the average number of people
+// checked in; Relative scales it so the busiest hour of the week is 100.
+type PopularTimes struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	HubID     primitive.ObjectID `bson:"hub_id" json:"hub_id"`
+	TimeZone  string             `bson:"timezone" json:"timezone"`
+	Occupancy [7][24]float64     `bson:"occupancy" json:"occupancy"`
+	Relative  [7][24]int         `bson:"relative" json:"relative"`
+	CheckIns  int                `bson:"checkins" json:"checkins"` // check-ins the histogram is built from
+	Weeks     float64            `bson:"weeks" json:"weeks"`       // span of history covered
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// PopularNow is the usual busyness for the current hour at a hub
+type PopularNow struct {
+	Weekday        int     `json:"weekday"`
+	Hour           int     `json:"hour"`
+	Occupancy      float64 `json:"occupancy"`
+	Relative       int     `json:"relative"`
+	Quiet          bool    `json:"quiet"`
+	ActiveCheckIns int64   `json:"active_checkins"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reportable content types
+const (
+	ReportTargetHub    = "hub"
+	ReportTargetReview = "review"
+	ReportTargetPhoto  = "photo"
+	ReportTargetUser   = "user"
+)
+
+// Report reason codes
+const (
+	ReportReasonSpam          = "spam"
+	ReportReasonOffensive     = "offensive"
+	ReportReasonHarassment    = "harassment"
+	ReportReasonFake          = "fake"
+	ReportReasonInappropriate = "inappropriate"
+	ReportReasonWrongInfo     = "wrong_info"
+	ReportReasonOther         = "other"
+)
+
+// Report states
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusActioned  = "actioned"
+	ReportStatusDismissed = "dismissed"
+)
+
+// Moderator actions
+const (
+	ModerationHide    = "hide"
+	ModerationDelete  = "delete"
+	ModerationWarn    = "warn"
+	ModerationDismiss = "dismiss"
+)
+
+// Report flags a piece of content for moderators. A zero ReporterID means
+// the report was raised automatically.
+type Report struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	TargetType    string              `bson:"target_type" json:"target_type"`
+	TargetID      primitive.ObjectID  `bson:"target_id" json:"target_id"`
+	HubID         *primitive.ObjectID `bson:"hub_id,omitempty" json:"hub_id,omitempty"` // hub the content belongs to
+	ReporterID    primitive.ObjectID  `bson:"reporter_id" json:"reporter_id"`
+	Reason        string              `bson:"reason" json:"reason"`
+	Details       string              `bson:"details,omitempty" json:"details,omitempty"`
+	Status        string              `bson:"status" json:"status"`
+	Action        string              `bson:"action,omitempty" json:"action,omitempty"`
+	WarnedUser    bool                `bson:"warned_user,omitempty" json:"warned_user,omitempty"`
+	ModeratorID   *primitive.ObjectID `bson:"moderator_id,omitempty" json:"moderator_id,omitempty"`
+	ModeratorNote string              `bson:"moderator_note,omitempty" json:"moderator_note,omitempty"`
+	ResolvedAt    *time.Time          `bson:"resolved_at,omitempty" json:"resolved_at,omitempty"`
+	CreatedAt     time.Time           `bson:"created_at" json:"created_at"`
+}
+
+// IsValidReportTarget reports whether t is a reportable content type
+func IsValidReportTarget(t string) bool {
+	switch t {
+	case ReportTargetHub, ReportTargetReview, ReportTargetPhoto, ReportTargetUser:
+		return true
+	}
+	return false
+}
+
+// IsValidReportReason reports whether r is a known reason code
+func IsValidReportReason(r string) bool {
+	switch r {
+	case ReportReasonSpam, ReportReasonOffensive, ReportReasonHarassment, ReportReasonFake,
+		ReportReasonInappropriate, ReportReasonWrongInfo, ReportReasonOther:
+		return true
+	}
+	return false
+}
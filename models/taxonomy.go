@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Taxonomy kinds curated by admins
+const (
+	TaxonomyCategory      = "category"      // vibe of the place, e.g. co-working, library
+	TaxonomyNeighbourhood = "neighbourhood" // area, e.g. kilimani, cbd
+)
+
+// TaxonomyTerm is an admin-curated category or neighbourhood. Hubs refer
+// to terms by slug.
+type TaxonomyTerm struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Kind        string             `bson:"kind" json:"kind"`
+	Slug        string             `bson:"slug" json:"slug"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	CreatedBy   primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+
+	// enriched
+	HubCount int `bson:"-" json:"hub_count"`
+}
+
+// TagSynonym maps a normalized user tag onto the tag it should be merged
+// into, e.g. "coworking" -> "co-working"
+type TagSynonym struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Alias     string             `bson:"alias" json:"alias"`
+	Tag       string             `bson:"tag" json:"tag"`
+	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// TagCount is a tag with the number of visible hubs carrying it
+type TagCount struct {
+	Tag      string `bson:"_id" json:"tag"`
+	HubCount int    `bson:"count" json:"hub_count"`
+}
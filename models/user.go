@@ -12,6 +12,9 @@ type User struct {
 	Email        string             `bson:"email" json:"email"`
 	Role      	 string             `bson:"role" json:"role"`           // e.g., host, manager, cleaner
 	Phone     	 string             `bson:"phone,omitempty" json:"phone,omitempty"`
+	Locale       string             `bson:"locale,omitempty" json:"locale,omitempty"` // email language, e.g. en, sw
+	VerifiedAt   *time.Time         `bson:"verified_at,omitempty" json:"verified_at,omitempty"`
+	Warnings     int                `bson:"warnings,omitempty" json:"warnings,omitempty"` // moderation warnings received
 	RefreshToken string             `bson:"refresh_token,omitempty" json:"-"`
 	OTP          string             `bson:"otp,omitempty" json:"-"`
 	OTPExpiry    time.Time          `bson:"otp_expiry,omitempty" json:"-"`
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WiFiSpeedTest is a speed test a user ran while at a hub
+type WiFiSpeedTest struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	HubID          primitive.ObjectID `bson:"hub_id" json:"hub_id"`
+	UserID         primitive.ObjectID `bson:"user_id" json:"user_id"`
+	DownloadMbps   float64            `bson:"download_mbps" json:"download_mbps"`
+	UploadMbps     float64            `bson:"upload_mbps" json:"upload_mbps"`
+	LatencyMs      float64            `bson:"latency_ms" json:"latency_ms"`
+	SSID           string             `bson:"ssid,omitempty" json:"ssid,omitempty"`
+	Coordinates    Coordinates        `bson:"coordinates" json:"coordinates"`
+	DistanceMeters float64            `bson:"distance_m" json:"distance_meters"`
+	MeasuredAt     time.Time          `bson:"measured_at" json:"measured_at"` // client clock
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+
+	// Outliers are kept, flagged, so a real change in speed can be
+	// recognised once several users measure it. Superseded tests predate
+	// such a change and no longer count.
+	Rejected       bool   `bson:"rejected,omitempty" json:"rejected,omitempty"`
+	RejectedMetric string `bson:"rejected_metric,omitempty" json:"rejected_metric,omitempty"`
+	Superseded     bool   `bson:"superseded,omitempty" json:"superseded,omitempty"`
+}
+
+// WiFiStats are rolling medians of a hub's recent speed tests
+type WiFiStats struct {
+	DownloadMbps float64   `bson:"download_mbps" json:"download_mbps"`
+	UploadMbps   float64   `bson:"upload_mbps" json:"upload_mbps"`
+	LatencyMs    float64   `bson:"latency_ms" json:"latency_ms"`
+	Samples      int       `bson:"samples" json:"samples"`
+	SSIDs        []string  `bson:"ssids,omitempty" json:"ssids,omitempty"`
+	UpdatedAt    time.Time `bson:"updated_at" json:"updated_at"`
+}
@@ -8,6 +8,8 @@ import (
 )
 
 func SetupRoutes(r *gin.Engine, cfg *config.Config) {
+	r.Use(middleware.BodyLimit(cfg.Uploads.MaxRequestBytes))
+
 	// public
 	r.POST("/auth/register", controllers.Register(cfg))
 	r.POST("/auth/login", controllers.Login(cfg))
@@ -17,6 +19,20 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config) {
 	r.POST("/auth/request-otp", controllers.RequestOTP(cfg))
 	r.POST("/auth/verify-otp", controllers.VerifyOTP(cfg))
 
+	// locally stored uploads
+	if cfg.Storage.Provider == "local" {
+		r.Static(cfg.Storage.LocalURLPrefix, cfg.Storage.LocalDir)
+	}
+
+	// shared collection links
+	r.GET("/shared/collections/:slug", controllers.GetSharedCollection(cfg))
+
+	// dev inbox, only exposed when emails are captured locally
+	if cfg.Mail.Transport == "dev" {
+		r.GET("/dev/mailbox", controllers.ListDevMailbox())
+		r.GET("/dev/mailbox/:id", controllers.GetDevMail())
+	}
+
 	// protected
 	auth := middleware.AuthMiddleware(cfg)
 
@@ -37,17 +53,124 @@ func SetupRoutes(r *gin.Engine, cfg *config.Config) {
 		notifs.PATCH("/:id/read", controllers.MarkNotificationRead(cfg))
 	}
 
+	me := r.Group("/me")
+	me.Use(auth)
+	{
+		me.GET("/favorites", controllers.ListFavorites(cfg))
+		me.GET("/claims", controllers.ListMyClaims(cfg))
+	}
+
+	collections := r.Group("/collections")
+	collections.Use(auth)
+	{
+		collections.POST("", controllers.CreateCollection(cfg))
+		collections.GET("", controllers.ListCollections(cfg))
+		collections.GET("/:id", controllers.GetCollection(cfg))
+		collections.PATCH("/:id", controllers.UpdateCollection(cfg))
+		collections.DELETE("/:id", controllers.DeleteCollection(cfg))
+		collections.POST("/:id/slug", controllers.RotateCollectionSlug(cfg))
+
+		collections.POST("/:id/items", controllers.AddCollectionItem(cfg))
+		collections.PATCH("/:id/items/order", controllers.ReorderCollectionItems(cfg))
+		collections.PATCH("/:id/items/:hubId", controllers.UpdateCollectionItem(cfg))
+		collections.DELETE("/:id/items/:hubId", controllers.RemoveCollectionItem(cfg))
+
+		collections.POST("/:id/invites", controllers.InviteCollaborator(cfg))
+		collections.POST("/:id/invites/accept", controllers.AcceptCollectionInvite(cfg))
+		collections.DELETE("/:id/collaborators/:userId", controllers.RemoveCollaborator(cfg))
+	}
+
+	taxonomy := r.Group("/taxonomy")
+	taxonomy.Use(auth)
+	{
+		taxonomy.GET("/:kind", controllers.ListTaxonomy(cfg))
+		taxonomy.POST("/:kind", controllers.CreateTaxonomyTerm(cfg))
+		taxonomy.PATCH("/:kind/:slug", controllers.UpdateTaxonomyTerm(cfg))
+		taxonomy.DELETE("/:kind/:slug", controllers.DeleteTaxonomyTerm(cfg))
+	}
+
+	tags := r.Group("/tags")
+	tags.Use(auth)
+	{
+		tags.GET("", controllers.ListTags(cfg))
+		tags.GET("/synonyms", controllers.ListTagSynonyms(cfg))
+		tags.POST("/synonyms", controllers.CreateTagSynonym(cfg))
+		tags.DELETE("/synonyms/:alias", controllers.DeleteTagSynonym(cfg))
+	}
+
 	// Events
 	hubs := r.Group("/hubs")
 	hubs.Use(auth)
 	{
 		hubs.POST("", controllers.CreateHub(cfg))
 		hubs.POST("/:id/reviews", controllers.AddReview(cfg))
-		hubs.POST("/:id/favorite", controllers.ToggleFavorite(cfg))
+		hubs.PATCH("/:id/reviews/:reviewId", controllers.UpdateReview(cfg))
+		hubs.POST("/:id/favorite", controllers.ToggleFavorite(cfg)) // deprecated
+		hubs.PUT("/:id/favorite", controllers.AddFavorite(cfg))
+		hubs.DELETE("/:id/favorite", controllers.RemoveFavorite(cfg))
 		hubs.GET("", controllers.ListHubs(cfg))
+		hubs.GET("/recommended", controllers.RecommendHubs(cfg))
+		hubs.GET("/clusters", controllers.ListHubClusters(cfg))
+		hubs.GET("/export", controllers.ExportHubs(cfg))
+		hubs.POST("/import", controllers.ImportHubs(cfg))
+		hubs.GET("/import", controllers.ListHubImports(cfg))
+		hubs.GET("/import/:id", controllers.GetHubImport(cfg))
 		hubs.GET("/:id", controllers.GetHub(cfg))
 		hubs.PATCH("/:id", controllers.UpdateHub(cfg))
 		hubs.DELETE("/:id", controllers.DeleteHub(cfg))
+
+		hubs.GET("/:id/photos", controllers.ListHubPhotos(cfg))
+		hubs.POST("/:id/photos", controllers.AddHubPhotos(cfg))
+		hubs.PATCH("/:id/photos/order", controllers.ReorderHubPhotos(cfg))
+		hubs.PATCH("/:id/photos/:photoId", controllers.UpdateHubPhoto(cfg))
+		hubs.DELETE("/:id/photos/:photoId", controllers.DeleteHubPhoto(cfg))
+
+		hubs.POST("/:id/edits", controllers.SuggestHubEdit(cfg))
+		hubs.GET("/:id/edits", controllers.ListHubEdits(cfg))
+		hubs.PATCH("/:id/edits/:editId", controllers.ReviewHubEdit(cfg))
+		hubs.GET("/:id/history", controllers.GetHubHistory(cfg))
+		hubs.POST("/:id/history/:revisionId/revert", controllers.RevertHub(cfg))
+
+		hubs.GET("/:id/duplicates", controllers.ListHubDuplicates(cfg))
+		hubs.POST("/:id/merge", controllers.MergeHub(cfg))
+
+		hubs.POST("/:id/checkins", controllers.CheckIn(cfg))
+		hubs.DELETE("/:id/checkins", controllers.CheckOut(cfg))
+		hubs.GET("/:id/popular-times", controllers.GetPopularTimes(cfg))
+
+		hubs.POST("/:id/wifi-tests", controllers.SubmitWiFiTest(cfg))
+		hubs.GET("/:id/wifi-tests", controllers.ListWiFiTests(cfg))
+
+		hubs.POST("/:id/claims", controllers.SubmitHubClaim(cfg))
+		hubs.GET("/:id/claims", controllers.ListHubClaims(cfg))
+		hubs.PUT("/:id/hours", controllers.SetHubHours(cfg))
+		hubs.PUT("/:id/reviews/:reviewId/reply", controllers.ReplyToReview(cfg))
+		hubs.DELETE("/:id/reviews/:reviewId/reply", controllers.DeleteReviewReply(cfg))
+	}
+
+	claims := r.Group("/claims")
+	claims.Use(auth) // admin actions checked per handler
+	{
+		claims.GET("", controllers.ListClaims(cfg))
+		claims.GET("/:claimId", controllers.GetClaim(cfg))
+		claims.POST("/:claimId/withdraw", controllers.WithdrawClaim(cfg))
+		claims.POST("/:claimId/approve", controllers.ApproveClaim(cfg))
+		claims.POST("/:claimId/reject", controllers.RejectClaim(cfg))
+		claims.POST("/:claimId/revoke", controllers.RevokeClaim(cfg))
+	}
+
+	reports := r.Group("/reports")
+	reports.Use(auth)
+	{
+		reports.POST("", controllers.CreateReport(cfg))
+	}
+
+	moderation := r.Group("/moderation")
+	moderation.Use(auth) // moderators and admins, checked per handler
+	{
+		moderation.GET("/reports", controllers.ListReports(cfg))
+		moderation.GET("/reports/:id", controllers.GetReport(cfg))
+		moderation.POST("/reports/:id/resolve", controllers.ResolveReport(cfg))
 	}
 
 
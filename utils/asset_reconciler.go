@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"context"
+	"log"
+	"time"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AssetReference is a field whose embedded assets keep stored files alive
+type AssetReference struct {
+	Collection string
+	Field      string // path to the embedded asset's _id, e.g. "images._id"
+}
+
+// AssetReferences lists everywhere an asset may be referenced from. An
+// asset not found in any of these is an orphan.
+var AssetReferences = []AssetReference{
+	{Collection: "hubs", Field: "images._id"},
+	{Collection: "events", Field: "images._id"},
+	{Collection: "reviews", Field: "images._id"},
+	{Collection: "hub_photos", Field: "asset._id"},
+	{Collection: "hub_claims", Field: "evidence._id"},
+}
+
+// ReconcileReport summarises one reconciler run
+type ReconcileReport struct {
+	StartedAt    time.Time     `json:"started_at"`
+	Duration     time.Duration `json:"duration"`
+	DryRun       bool          `json:"dry_run"`
+	Scanned      int           `json:"scanned"`       // assets older than the grace period
+	Referenced   int           `json:"referenced"`    // still in use
+	Orphaned     int           `json:"orphaned"`      // unreferenced
+	Deleted      int           `json:"deleted"`       // orphans removed (0 in dry-run)
+	Failed       int           `json:"failed"`        // orphans that could not be removed
+	Foreign      int           `json:"foreign"`       // orphans in another storage provider
+	BytesFreed   int64         `json:"bytes_freed"`   // or would be freed, in dry-run
+	OrphanedURLs []string      `json:"orphaned_urls"` // capped sample for the log
+}
+
+// orphanSampleSize caps how many orphan URLs a report lists
+const orphanSampleSize = 20
+
+// StartAssetReconciler runs ReconcileAssets every cfg.AssetGC.Interval in
+// the background. An interval of 0 disables it.
+func StartAssetReconciler(cfg *config.Config) {
+	if cfg.AssetGC.Interval <= 0 {
+		log.Println("🧹 Asset reconciler disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(cfg.AssetGC.Interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			report, err := ReconcileAssets(cfg, cfg.AssetGC.DryRun)
+			if err != nil {
+				log.Printf("⚠️ Asset reconciler failed: %v", err)
+				continue
+			}
+			logReconcileReport(report)
+		}
+	}()
+	log.Printf("🧹 Asset reconciler every %s (grace %s, dry-run %t)", cfg.AssetGC.Interval, cfg.AssetGC.Grace, cfg.AssetGC.DryRun)
+}
+
+// ReconcileAssets compares recorded assets against every AssetReference
+// and deletes those unreferenced for longer than the grace period.
+func ReconcileAssets(cfg *config.Config, dryRun bool) (ReconcileReport, error) {
+	report := ReconcileReport{StartedAt: time.Now(), DryRun: dryRun, OrphanedURLs: []string{}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	db := cfg.MongoClient.Database(cfg.DBName)
+
+	referenced := map[primitive.ObjectID]bool{}
+	for _, ref := range AssetReferences {
+		ids, err := db.Collection(ref.Collection).Distinct(ctx, ref.Field, bson.M{})
+		if err != nil {
+			return report, err
+		}
+		for _, id := range ids {
+			if oid, ok := id.(primitive.ObjectID); ok {
+				referenced[oid] = true
+			}
+		}
+	}
+
+	cutoff := report.StartedAt.Add(-cfg.AssetGC.Grace)
+	cursor, err := db.Collection("assets").Find(ctx, bson.M{"created_at": bson.M{"$lt": cutoff}})
+	if err != nil {
+		return report, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var asset models.Asset
+		if err := cursor.Decode(&asset); err != nil {
+			return report, err
+		}
+		report.Scanned++
+
+		if referenced[asset.ID] {
+			report.Referenced++
+			continue
+		}
+
+		report.Orphaned++
+		report.BytesFreed += assetBytes(asset)
+		if len(report.OrphanedURLs) < orphanSampleSize {
+			report.OrphanedURLs = append(report.OrphanedURLs, asset.URL)
+		}
+
+		if storage == nil || asset.Provider != storage.Name() {
+			report.Foreign++
+			continue
+		}
+		if dryRun {
+			continue
+		}
+		if err := DeleteAsset(cfg, asset); err != nil {
+			log.Printf("⚠️ Asset reconciler: could not delete %s: %v", asset.ID.Hex(), err)
+			report.Failed++
+			report.BytesFreed -= assetBytes(asset)
+			continue
+		}
+		report.Deleted++
+	}
+
+	report.Duration = time.Since(report.StartedAt)
+	return report, cursor.Err()
+}
+
+// assetBytes totals the stored size of every variant
+func assetBytes(a models.Asset) int64 {
+	if len(a.Variants) == 0 {
+		return a.Size
+	}
+	var n int64
+	for _, v := range a.Variants {
+		n += v.Size
+	}
+	return n
+}
+
+func logReconcileReport(r ReconcileReport) {
+	mode := ""
+	if r.DryRun {
+		mode = " [dry-run]"
+	}
+	log.Printf("🧹 Asset reconcile%s: scanned=%d referenced=%d orphaned=%d deleted=%d failed=%d foreign=%d freed=%s in %s",
+		mode, r.Scanned, r.Referenced, r.Orphaned, r.Deleted, r.Failed, r.Foreign, humanBytes(r.BytesFreed), r.Duration.Round(time.Millisecond))
+	for _, u := range r.OrphanedURLs {
+		log.Printf("🧹   orphan: %s", u)
+	}
+}
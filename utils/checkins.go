@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// liveReportLimit caps the recent reports returned with a hub
+const liveReportLimit = 10
+
+// checkInSweepInterval is how often expired check-ins are closed
+const checkInSweepInterval = 5 * time.Minute
+
+// HubLiveStatus counts active check-ins at a hub and summarises the
+// crowd, noise and Wi-Fi reports made within cfg.CheckIns.ReportWindow.
+func HubLiveStatus(ctx context.Context, cfg *config.Config, hubID primitive.ObjectID) (models.LiveStatus, error) {
+	col := cfg.MongoClient.Database(cfg.DBName).Collection("checkins")
+	now := time.Now()
+	status := models.LiveStatus{Reports: []models.LiveReport{}}
+
+	active, err := col.CountDocuments(ctx, bson.M{
+		"hub_id":     hubID,
+		"active":     true,
+		"expires_at": bson.M{"$gt": now},
+	})
+	if err != nil {
+		return status, err
+	}
+	status.ActiveCheckIns = active
+
+	cursor, err := col.Find(ctx,
+		bson.M{"hub_id": hubID, "reported_at": bson.M{"$gte": now.Add(-cfg.CheckIns.ReportWindow)}},
+		options.Find().SetSort(bson.M{"reported_at": -1}).SetLimit(200),
+	)
+	if err != nil {
+		return status, err
+	}
+	var checkIns []models.CheckIn
+	if err := cursor.All(ctx, &checkIns); err != nil {
+		return status, err
+	}
+
+	reports := make([]models.LiveReport, 0, len(checkIns))
+	for _, ci := range checkIns {
+		reports = append(reports, models.LiveReport{
+			Crowd:      ci.Crowd,
+			Noise:      ci.Noise,
+			WiFi:       ci.WiFi,
+			Verified:   ci.Verified,
+			ReportedAt: *ci.ReportedAt,
+		})
+	}
+
+	halfLife := cfg.CheckIns.ReportHalfLife
+	status.Crowd = DecayedLevel(reports, func(r models.LiveReport) *int { return r.Crowd }, now, halfLife)
+	status.Noise = DecayedLevel(reports, func(r models.LiveReport) *int { return r.Noise }, now, halfLife)
+	status.WiFi = DecayedLevel(reports, func(r models.LiveReport) *int { return r.WiFi }, now, halfLife)
+	if len(reports) > liveReportLimit {
+		reports = reports[:liveReportLimit]
+	}
+	status.Reports = reports
+	return status, nil
+}
+
+// DecayedLevel averages the values pick returns, weighting each report by
+// 0.5^(age/halfLife). Verified reports count double. Returns nil when no
+// report carries a value.
+func DecayedLevel(reports []models.LiveReport, pick func(models.LiveReport) *int, now time.Time, halfLife time.Duration) *float64 {
+	var sum, weights float64
+	for _, r := range reports {
+		v := pick(r)
+		if v == nil {
+			continue
+		}
+		w := 1.0
+		if halfLife > 0 {
+			age := math.Max(0, now.Sub(r.ReportedAt).Seconds())
+			w = math.Pow(0.5, age/halfLife.Seconds())
+		}
+		if r.Verified {
+			w *= 2
+		}
+		sum += w * float64(*v)
+		weights += w
+	}
+	if weights == 0 {
+		return nil
+	}
+	level := math.Round(sum/weights*10) / 10
+	return &level
+}
+
+// ExpireCheckIns closes check-ins whose timeout has passed
+func ExpireCheckIns(ctx context.Context, cfg *config.Config) (int64, error) {
+	col := cfg.MongoClient.Database(cfg.DBName).Collection("checkins")
+	now := time.Now()
+	res, err := col.UpdateMany(ctx,
+		bson.M{"active": true, "expires_at": bson.M{"$lte": now}},
+		bson.A{bson.M{"$set": bson.M{
+			"active":           false,
+			"checked_out_at":   "$expires_at",
+			"auto_checked_out": true,
+		}}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// StartCheckInSweeper periodically checks out users who never did
+func StartCheckInSweeper(cfg *config.Config) {
+	go func() {
+		ticker := time.NewTicker(checkInSweepInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			n, err := ExpireCheckIns(ctx, cfg)
+			cancel()
+			if err != nil {
+				log.Printf("⚠️ Check-in sweep failed: %v", err)
+			} else if n > 0 {
+				log.Printf("🚪 Auto checked out %d check-in(s)", n)
+			}
+		}
+	}()
+	log.Printf("🚪 Check-ins time out after %s", cfg.CheckIns.Timeout)
+}
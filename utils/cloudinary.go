@@ -3,113 +3,62 @@ package utils
 import (
 	"context"
 	"fmt"
-	"mime/multipart"
-	"net/url"
-	"os"
+	"io"
 	"path"
 	"strings"
-	"time"
 
 	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api"
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+
+	models "github.com/phillip/contribution-tracker-go/models"
 )
 
-func getCloudinaryInstance() (*cloudinary.Cloudinary, error) {
-	return cloudinary.NewFromParams(
-		os.Getenv("CLOUDINARY_CLOUD_NAME"),
-		os.Getenv("CLOUDINARY_API_KEY"),
-		os.Getenv("CLOUDINARY_API_SECRET"),
-	)
+// CloudinaryStorage stores files in Cloudinary using one shared client
+type CloudinaryStorage struct {
+	cld *cloudinary.Cloudinary
 }
 
-// ✅ Upload to "events" folder
-func UploadToCloudinary(file multipart.File, fileHeader *multipart.FileHeader) (string, error) {
-	cld, err := getCloudinaryInstance()
-	if err != nil {
-		return "", fmt.Errorf("cloudinary config error: %v", err)
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
-
-	uploadResp, err := cld.Upload.Upload(ctx, file, uploader.UploadParams{
-		Folder: "events",
-	})
+func NewCloudinaryStorage(cloudName, apiKey, apiSecret string) (*CloudinaryStorage, error) {
+	cld, err := cloudinary.NewFromParams(cloudName, apiKey, apiSecret)
 	if err != nil {
-		return "", fmt.Errorf("upload error: %v", err)
+		return nil, fmt.Errorf("cloudinary config error: %v", err)
 	}
-
-	return uploadResp.SecureURL, nil
+	return &CloudinaryStorage{cld: cld}, nil
 }
 
-// ✅ Upload to "damages" folder
-func UploadDamagesToCloudinary(file multipart.File, fileHeader *multipart.FileHeader) (string, error) {
-	cld, err := getCloudinaryInstance()
-	if err != nil {
-		return "", fmt.Errorf("cloudinary config error: %v", err)
-	}
+func (s *CloudinaryStorage) Name() string { return models.ProviderCloudinary }
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
+func (s *CloudinaryStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (StoredObject, error) {
+	// Cloudinary public IDs carry no extension, the format is tracked separately
+	publicID := strings.TrimSuffix(key, path.Ext(key))
 
-	uploadResp, err := cld.Upload.Upload(ctx, file, uploader.UploadParams{
-		Folder: "damages",
+	uploadResp, err := s.cld.Upload.Upload(ctx, r, uploader.UploadParams{
+		PublicID:       publicID,
+		UniqueFilename: api.Bool(false),
+		Overwrite:      api.Bool(false),
 	})
 	if err != nil {
-		return "", fmt.Errorf("upload error: %v", err)
+		return StoredObject{}, err
 	}
-
-	return uploadResp.SecureURL, nil
-}
-
-// ✅ Delete image from Cloudinary using full URL
-func DeleteFromCloudinary(imageURL string) error {
-	cld, err := getCloudinaryInstance()
-	if err != nil {
-		return fmt.Errorf("cloudinary config error: %v", err)
+	if uploadResp.Error.Message != "" {
+		return StoredObject{}, fmt.Errorf("cloudinary: %s", uploadResp.Error.Message)
 	}
 
-	// Extract public ID from URL
-	publicID, err := extractPublicID(imageURL)
-	if err != nil {
-		return fmt.Errorf("could not extract public ID: %v", err)
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	_, err = cld.Upload.Destroy(ctx, uploader.DestroyParams{
-		PublicID: publicID,
-	})
-	if err != nil {
-		return fmt.Errorf("delete error: %v", err)
-	}
-
-	return nil
+	return StoredObject{
+		Key:  uploadResp.PublicID,
+		URL:  uploadResp.SecureURL,
+		Size: int64(uploadResp.Bytes),
+	}, nil
 }
 
-// 🔹 Helper: Extract Cloudinary public ID from full URL
-func extractPublicID(imageURL string) (string, error) {
-	parsedURL, err := url.Parse(imageURL)
+func (s *CloudinaryStorage) Delete(ctx context.Context, key string) error {
+	resp, err := s.cld.Upload.Destroy(ctx, uploader.DestroyParams{PublicID: key})
 	if err != nil {
-		return "", err
+		return err
 	}
-
-	// Example: https://res.cloudinary.com/demo/image/upload/v1234567890/events/abc123.jpg
-	parts := strings.Split(parsedURL.Path, "/")
-
-	if len(parts) < 3 {
-		return "", fmt.Errorf("invalid cloudinary URL format")
-	}
-
-	// Remove version part (e.g., v1234567890)
-	cleanPath := parts[len(parts)-2:]
-	if strings.HasPrefix(cleanPath[0], "v") {
-		parts = append(parts[:len(parts)-2], parts[len(parts)-1])
+	if resp.Error.Message != "" {
+		return fmt.Errorf("cloudinary: %s", resp.Error.Message)
 	}
-
-	// Get everything after /upload/ (folder + filename without extension)
-	publicID := strings.TrimSuffix(path.Join(parts[3:]...), path.Ext(parts[len(parts)-1]))
-
-	return publicID, nil
+	return nil
 }
@@ -48,4 +48,13 @@ func Decrypt(aesKey []byte, encoded string) (string, error) {
 	stream := cipher.NewCFBDecrypter(block, iv)
 	stream.XORKeyStream(ct, ct)
 	return string(ct), nil
-}
\ No newline at end of file
+}
+
+// RandomToken returns n random bytes encoded for use in URLs
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
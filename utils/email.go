@@ -2,89 +2,165 @@ package utils
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"log"
-	"net/http"
-	"os"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+
+	config "github.com/phillip/contribution-tracker-go/config"
 )
 
-// email request payload for ZeptoMail API
-type emailRequest struct {
-	From     emailAddress   `json:"from"`
-	To       []toRecipient  `json:"to"`
-	Subject  string         `json:"subject"`
-	HtmlBody string         `json:"htmlbody"`
+// EmailRecipient is a single addressee with an optional display name
+type EmailRecipient struct {
+	Address string
+	Name    string
 }
 
-type emailAddress struct {
-	Address string `json:"address"`
+// EmailMessage is a transport-agnostic email. Either or both of HTML and Text
+// may be set; when both are present the message is sent as multipart/alternative.
+type EmailMessage struct {
+	To      []EmailRecipient
+	Subject string
+	HTML    string
+	Text    string
 }
 
-type toRecipient struct {
-	Email emailWithName `json:"email_address"`
+// Mailer delivers an EmailMessage through a concrete transport
+type Mailer interface {
+	Send(ctx context.Context, msg EmailMessage) error
 }
 
-type emailWithName struct {
-	Address string `json:"address"`
-	Name    string `json:"name"`
-}
+var mailer Mailer
 
-// SendEmail sends an HTML email using the ZeptoMail HTTP API
-func SendEmail(to, subject, body string) error {
-	apiURL := os.Getenv("ZEPTO_API_URL")     // e.g. https://api.zeptomail.com/v1.1/email
-	apiKey := os.Getenv("ZEPTO_API_KEY")     // e.g. Zoho-enczapikey xxxxx
-	from := os.Getenv("EMAIL_FROM")          // e.g. noreply@subsafe.co.ke
-	toName := os.Getenv("EMAIL_TO_NAME")     // e.g. "User" or any name fallback
+// InitMailer builds the transport selected by cfg.Mail.Transport and makes it
+// the one used by SendEmail.
+func InitMailer(cfg *config.Config) (Mailer, error) {
+	from := EmailRecipient{Address: cfg.Mail.From, Name: cfg.Mail.FromName}
 
-	if apiURL == "" || apiKey == "" || from == "" {
-		log.Println("Missing ZEPTO_API_URL, ZEPTO_API_KEY, or EMAIL_FROM")
-		return fmt.Errorf("missing required email config")
+	var m Mailer
+	switch cfg.Mail.Transport {
+	case "zeptomail":
+		m = NewZeptoMailer(cfg.Mail.ZeptoAPIURL, cfg.Mail.ZeptoAPIKey, from)
+	case "smtp":
+		m = NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, from)
+	case "dev":
+		dev, err := NewDevMailer(cfg.Mail.DevDir, from)
+		if err != nil {
+			return nil, err
+		}
+		m = dev
+	default:
+		return nil, fmt.Errorf("unknown mail transport %q", cfg.Mail.Transport)
 	}
 
-	payload := emailRequest{
-		From: emailAddress{Address: from},
-		To: []toRecipient{
-			{
-				Email: emailWithName{
-					Address: to,
-					Name:    toName,
-				},
-			},
-		},
-		Subject:  subject,
-		HtmlBody: body,
+	mailer = m
+	log.Printf("📧 Mail transport: %s", cfg.Mail.Transport)
+	return m, nil
+}
+
+// SendEmail delivers msg with the configured transport
+func SendEmail(msg EmailMessage) error {
+	if mailer == nil {
+		log.Println("mailer not initialised, dropping email")
+		return fmt.Errorf("mailer not initialised")
+	}
+	if len(msg.To) == 0 {
+		return fmt.Errorf("email has no recipients")
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		log.Printf("Failed to marshal email payload: %v", err)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := mailer.Send(ctx, msg); err != nil {
+		log.Printf("Failed to send email %q: %v", msg.Subject, err)
 		return err
 	}
 
-	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		log.Printf("Failed to create request: %v", err)
-		return err
+	log.Printf("Email successfully sent to %s", msg.To[0].Address)
+	return nil
+}
+
+// formatAddress renders a recipient as an RFC 5322 address
+func formatAddress(r EmailRecipient) string {
+	return (&mail.Address{Name: r.Name, Address: r.Address}).String()
+}
+
+// buildMIME renders msg as a raw RFC 5322 message, used by SMTP and the dev sink
+func buildMIME(from EmailRecipient, msg EmailMessage) ([]byte, error) {
+	var buf bytes.Buffer
+
+	to := make([]string, 0, len(msg.To))
+	for _, r := range msg.To {
+		to = append(to, formatAddress(r))
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", apiKey)
+	idBytes := make([]byte, 12)
+	_, _ = rand.Read(idBytes)
+	domain := "localhost"
+	if at := strings.LastIndex(from.Address, "@"); at >= 0 {
+		domain = from.Address[at+1:]
+	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Printf("Failed to send email: %v", err)
-		return err
+	fmt.Fprintf(&buf, "From: %s\r\n", formatAddress(from))
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(idBytes), domain)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	// Single part
+	if msg.HTML == "" || msg.Text == "" {
+		contentType, body := "text/plain", msg.Text
+		if msg.HTML != "" {
+			contentType, body = "text/html", msg.HTML
+		}
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=utf-8\r\n", contentType)
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(&buf, body); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
-		log.Printf("ZeptoMail returned status %s", resp.Status)
-		return fmt.Errorf("zeptomail API error: %s", resp.Status)
+	// multipart/alternative: plain text first, HTML last (preferred)
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", mw.Boundary())
+
+	parts := []struct{ contentType, body string }{
+		{"text/plain", msg.Text},
+		{"text/html", msg.HTML},
+	}
+	for _, p := range parts {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, p.body); err != nil {
+			return nil, err
+		}
 	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
 
-	log.Printf("Email successfully sent to %s", to)
-	return nil
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
 }
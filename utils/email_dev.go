@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// devMailboxSize caps how many messages the in-memory dev inbox keeps
+const devMailboxSize = 100
+
+// DevMail is a captured message shown in the /dev/mailbox inbox
+type DevMail struct {
+	ID      string           `json:"id"`
+	To      []EmailRecipient `json:"to"`
+	Subject string           `json:"subject"`
+	HTML    string           `json:"html,omitempty"`
+	Text    string           `json:"text,omitempty"`
+	SentAt  time.Time        `json:"sent_at"`
+	File    string           `json:"file,omitempty"`
+}
+
+// DevMailer never delivers anything. It keeps the latest messages in memory
+// and, when dir is set, also writes each one to disk as a .eml file.
+type DevMailer struct {
+	dir  string
+	from EmailRecipient
+
+	mu       sync.RWMutex
+	messages []DevMail
+}
+
+var devMailer *DevMailer
+
+func NewDevMailer(dir string, from EmailRecipient) (*DevMailer, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create mail dir: %w", err)
+		}
+	}
+	devMailer = &DevMailer{dir: dir, from: from}
+	return devMailer, nil
+}
+
+func (d *DevMailer) Send(ctx context.Context, msg EmailMessage) error {
+	m := DevMail{
+		ID:      primitive.NewObjectID().Hex(),
+		To:      msg.To,
+		Subject: msg.Subject,
+		HTML:    msg.HTML,
+		Text:    msg.Text,
+		SentAt:  time.Now(),
+	}
+
+	if d.dir != "" {
+		raw, err := buildMIME(d.from, msg)
+		if err != nil {
+			return fmt.Errorf("build message: %w", err)
+		}
+		m.File = filepath.Join(d.dir, m.SentAt.Format("20060102-150405")+"-"+m.ID+".eml")
+		if err := os.WriteFile(m.File, raw, 0o644); err != nil {
+			return fmt.Errorf("write eml: %w", err)
+		}
+	}
+
+	d.mu.Lock()
+	d.messages = append([]DevMail{m}, d.messages...)
+	if len(d.messages) > devMailboxSize {
+		d.messages = d.messages[:devMailboxSize]
+	}
+	d.mu.Unlock()
+	return nil
+}
+
+// Messages returns captured messages, newest first
+func (d *DevMailer) Messages() []DevMail {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	out := make([]DevMail, len(d.messages))
+	copy(out, d.messages)
+	return out
+}
+
+// DevMailbox returns the active dev mailer, or nil when another transport is in use
+func DevMailbox() *DevMailer {
+	return devMailer
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+)
+
+// SMTPMailer sends email over plain SMTP, upgrading the connection with STARTTLS
+type SMTPMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     EmailRecipient
+}
+
+func NewSMTPMailer(host, port, username, password string, from EmailRecipient) *SMTPMailer {
+	return &SMTPMailer{host: host, port: port, username: username, password: password, from: from}
+}
+
+func (s *SMTPMailer) Send(ctx context.Context, msg EmailMessage) error {
+	raw, err := buildMIME(s.from, msg)
+	if err != nil {
+		return fmt.Errorf("build message: %w", err)
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(s.host, s.port))
+	if err != nil {
+		return fmt.Errorf("smtp dial: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer client.Close()
+
+	// Never send credentials or mail over an unencrypted connection
+	if ok, _ := client.Extension("STARTTLS"); !ok {
+		return fmt.Errorf("smtp server %s does not support STARTTLS", s.host)
+	}
+	if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+		return fmt.Errorf("smtp starttls: %w", err)
+	}
+
+	if s.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.from.Address); err != nil {
+		return fmt.Errorf("smtp MAIL FROM: %w", err)
+	}
+	for _, r := range msg.To {
+		if err := client.Rcpt(r.Address); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s: %w", r.Address, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA: %w", err)
+	}
+	if _, err := w.Write(raw); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp DATA close: %w", err)
+	}
+
+	return client.Quit()
+}
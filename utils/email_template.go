@@ -1,34 +1,225 @@
 package utils
 
 import (
+	"bytes"
+	"embed"
+	"encoding/json"
 	"fmt"
+	htmltemplate "html/template"
+	"path"
+	"strings"
+	texttemplate "text/template"
 	"time"
+
+	models "github.com/phillip/contribution-tracker-go/models"
+)
+
+//go:embed templates/email
+var emailTemplateFS embed.FS
+
+const (
+	emailTemplateDir = "templates/email"
+	emailAppName     = "Laptopers"
+
+	// DefaultLocale is used when a user has no preference or an unsupported one
+	DefaultLocale = "en"
+)
+
+// Email template names, each backed by <name>.html and <name>.txt
+const (
+	EmailOTP         = "otp"
+	EmailWelcome     = "welcome"
+	EmailReviewReply = "review_reply"
+	EmailEventUpdate = "event_update"
+)
+
+var (
+	emailLocales = map[string]map[string]string{}
+	emailHTML    = map[string]*htmltemplate.Template{}
+	emailText    = map[string]*texttemplate.Template{}
 )
 
-func BuildOtpEmail(name, otp string) string {
-	year := time.Now().Year()
-	return fmt.Sprintf(`
-		<div style="font-family: Arial, sans-serif; background: #f9f9f9; padding: 20px;">
-		  <div style="max-width: 500px; margin: auto; background: #ffffff; border-radius: 10px; overflow: hidden; box-shadow: 0 4px 6px rgba(0,0,0,0.1);">
-			
-			<div style="background: #193730; padding: 15px; text-align: center;">
-			</div>
-			
-			<div style="padding: 20px; text-align: center;">
-			  <h2 style="color: #333;">Hello %s 👋</h2>
-			  <p style="color: #555;">Here’s your one-time password. It is valid for <b>10 minutes</b>.</p>
-			  
-			  <div style="font-size: 32px; font-weight: bold; color: #193730; margin: 20px 0;">
-				%s
-			  </div>
-			  
-			  <p style="color: #999;">If you didn’t request this, please ignore this email.</p>
-			</div>
-			
-			<div style="background: #f1f1f1; padding: 15px; text-align: center; font-size: 12px; color: #777;">
-			  &copy; %d Vault. All rights reserved.
-			</div>
-		  </div>
-		</div>
-	`, name, otp, year)
+func init() {
+	if err := loadEmailTemplates(); err != nil {
+		panic(fmt.Sprintf("email templates: %v", err))
+	}
+}
+
+// loadEmailTemplates parses locale bundles and pairs every content template
+// with the shared layout, once at startup.
+func loadEmailTemplates() error {
+	bundles, err := emailTemplateFS.ReadDir(path.Join(emailTemplateDir, "locales"))
+	if err != nil {
+		return err
+	}
+	for _, b := range bundles {
+		raw, err := emailTemplateFS.ReadFile(path.Join(emailTemplateDir, "locales", b.Name()))
+		if err != nil {
+			return err
+		}
+		strs := map[string]string{}
+		if err := json.Unmarshal(raw, &strs); err != nil {
+			return fmt.Errorf("locale %s: %w", b.Name(), err)
+		}
+		emailLocales[strings.TrimSuffix(b.Name(), ".json")] = strs
+	}
+	if _, ok := emailLocales[DefaultLocale]; !ok {
+		return fmt.Errorf("missing %s locale bundle", DefaultLocale)
+	}
+
+	// placeholder funcs, replaced per render with the recipient's locale
+	funcs := map[string]any{"t": func(string, ...any) string { return "" }}
+
+	for _, name := range []string{EmailOTP, EmailWelcome, EmailReviewReply, EmailEventUpdate} {
+		h, err := htmltemplate.New(name).Funcs(funcs).ParseFS(emailTemplateFS,
+			path.Join(emailTemplateDir, "layout.html"), path.Join(emailTemplateDir, name+".html"))
+		if err != nil {
+			return err
+		}
+		t, err := texttemplate.New(name).Funcs(funcs).ParseFS(emailTemplateFS,
+			path.Join(emailTemplateDir, "layout.txt"), path.Join(emailTemplateDir, name+".txt"))
+		if err != nil {
+			return err
+		}
+		emailHTML[name] = h
+		emailText[name] = t
+	}
+	return nil
+}
+
+// NormalizeLocale maps a user preference or Accept-Language value such as
+// "sw-KE" onto a supported locale, falling back to DefaultLocale.
+func NormalizeLocale(locale string) string {
+	for _, part := range strings.Split(locale, ",") {
+		tag := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		tag = strings.SplitN(strings.ReplaceAll(tag, "_", "-"), "-", 2)[0]
+		if _, ok := emailLocales[tag]; ok {
+			return tag
+		}
+	}
+	return DefaultLocale
+}
+
+// IsSupportedLocale reports whether a bundle exists for locale
+func IsSupportedLocale(locale string) bool {
+	_, ok := emailLocales[locale]
+	return ok
+}
+
+// translator returns the "t" template func for a locale. Missing keys fall
+// back to English, then to the key itself.
+func translator(locale string) func(string, ...any) string {
+	return func(key string, args ...any) string {
+		format, ok := emailLocales[locale][key]
+		if !ok {
+			format, ok = emailLocales[DefaultLocale][key]
+		}
+		if !ok {
+			return key
+		}
+		if len(args) == 0 {
+			return format
+		}
+		return fmt.Sprintf(format, args...)
+	}
+}
+
+// RenderEmail executes a named template in the given locale and returns the
+// subject plus HTML and plain-text bodies.
+func RenderEmail(name, locale string, data map[string]any) (subject, html, text string, err error) {
+	h, ok := emailHTML[name]
+	if !ok {
+		return "", "", "", fmt.Errorf("unknown email template %q", name)
+	}
+	locale = NormalizeLocale(locale)
+
+	vars := map[string]any{
+		"AppName": emailAppName,
+		"Year":    time.Now().Year(),
+		"Locale":  locale,
+	}
+	for k, v := range data {
+		vars[k] = v
+	}
+	funcs := map[string]any{"t": translator(locale)}
+
+	hc, err := h.Clone()
+	if err != nil {
+		return "", "", "", err
+	}
+	var htmlBuf bytes.Buffer
+	if err := hc.Funcs(funcs).ExecuteTemplate(&htmlBuf, "layout", vars); err != nil {
+		return "", "", "", fmt.Errorf("render %s.html: %w", name, err)
+	}
+
+	tc, err := emailText[name].Clone()
+	if err != nil {
+		return "", "", "", err
+	}
+	tc.Funcs(funcs)
+	var textBuf, subjectBuf bytes.Buffer
+	if err := tc.ExecuteTemplate(&textBuf, "layout", vars); err != nil {
+		return "", "", "", fmt.Errorf("render %s.txt: %w", name, err)
+	}
+	if err := tc.ExecuteTemplate(&subjectBuf, "subject", vars); err != nil {
+		return "", "", "", fmt.Errorf("render %s subject: %w", name, err)
+	}
+
+	return strings.TrimSpace(subjectBuf.String()), htmlBuf.String(), strings.TrimSpace(textBuf.String()) + "\n", nil
+}
+
+// SendTemplatedEmail renders a template in the user's locale and sends it to them
+func SendTemplatedEmail(user models.User, name string, data map[string]any) error {
+	if data == nil {
+		data = map[string]any{}
+	}
+	if _, ok := data["Name"]; !ok {
+		data["Name"] = displayName(user)
+	}
+
+	subject, html, text, err := RenderEmail(name, user.Locale, data)
+	if err != nil {
+		return err
+	}
+	return SendEmail(EmailMessage{
+		To:      []EmailRecipient{{Address: user.Email, Name: user.Name}},
+		Subject: subject,
+		HTML:    html,
+		Text:    text,
+	})
+}
+
+// SendOTPEmail sends a one-time password. purpose is "verify" or "login".
+func SendOTPEmail(user models.User, otp, purpose string, validFor time.Duration) error {
+	return SendTemplatedEmail(user, EmailOTP, map[string]any{
+		"OTP":          otp,
+		"Purpose":      purpose,
+		"ValidMinutes": int(validFor.Minutes()),
+	})
+}
+
+// SendWelcomeEmail greets a newly verified user
+func SendWelcomeEmail(user models.User) error {
+	return SendTemplatedEmail(user, EmailWelcome, nil)
+}
+
+// SendReviewReplyEmail tells a reviewer that replier answered their review
+// of hubTitle
+func SendReviewReplyEmail(user models.User, replier, hubTitle, reply string) error {
+	return SendTemplatedEmail(user, EmailReviewReply, map[string]any{
+		"ReplierName": replier,
+		"HubTitle":    hubTitle,
+		"Reply":       reply,
+	})
+}
+
+// displayName prefers the user's first name over their email address
+func displayName(user models.User) string {
+	if fields := strings.Fields(user.Name); len(fields) > 0 {
+		return fields[0]
+	}
+	if at := strings.Index(user.Email, "@"); at > 0 {
+		return user.Email[:at]
+	}
+	return user.Email
 }
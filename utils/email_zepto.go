@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// email request payload for ZeptoMail API
+type emailRequest struct {
+	From     emailWithName `json:"from"`
+	To       []toRecipient `json:"to"`
+	Subject  string        `json:"subject"`
+	HtmlBody string        `json:"htmlbody,omitempty"`
+	TextBody string        `json:"textbody,omitempty"`
+}
+
+type toRecipient struct {
+	Email emailWithName `json:"email_address"`
+}
+
+type emailWithName struct {
+	Address string `json:"address"`
+	Name    string `json:"name,omitempty"`
+}
+
+// ZeptoMailer sends email through the ZeptoMail HTTP API
+type ZeptoMailer struct {
+	apiURL string // e.g. https://api.zeptomail.com/v1.1/email
+	apiKey string // e.g. Zoho-enczapikey xxxxx
+	from   EmailRecipient
+	client *http.Client
+}
+
+func NewZeptoMailer(apiURL, apiKey string, from EmailRecipient) *ZeptoMailer {
+	return &ZeptoMailer{
+		apiURL: apiURL,
+		apiKey: apiKey,
+		from:   from,
+		client: &http.Client{Timeout: 20 * time.Second},
+	}
+}
+
+func (z *ZeptoMailer) Send(ctx context.Context, msg EmailMessage) error {
+	payload := emailRequest{
+		From:     emailWithName{Address: z.from.Address, Name: z.from.Name},
+		Subject:  msg.Subject,
+		HtmlBody: msg.HTML,
+		TextBody: msg.Text,
+	}
+	for _, r := range msg.To {
+		payload.To = append(payload.To, toRecipient{Email: emailWithName{Address: r.Address, Name: r.Name}})
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal email payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, z.apiURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", z.apiKey)
+
+	resp, err := z.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("zeptomail API error: %s", resp.Status)
+	}
+	return nil
+}
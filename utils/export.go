@@ -0,0 +1,285 @@
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	models "github.com/phillip/contribution-tracker-go/models"
+)
+
+// Hub export formats
+const (
+	ExportGeoJSON = "geojson"
+	ExportCSV     = "csv"
+	ExportKML     = "kml"
+)
+
+// HubExportRow is one hub with the aggregates an export includes
+type HubExportRow struct {
+	Hub       models.Hub
+	AvgRating float64 // of published reviews
+	Reviews   int
+}
+
+// HubExporter writes hubs one at a time, so an export never holds more
+// than one row in memory. Call Begin once, Write per hub, then End.
+type HubExporter interface {
+	ContentType() string
+	Extension() string
+	Begin() error
+	Write(row HubExportRow) error
+	End() error
+}
+
+// NewHubExporter returns the exporter for format, writing to w
+func NewHubExporter(format string, w io.Writer) (HubExporter, error) {
+	switch format {
+	case ExportGeoJSON:
+		return &geoJSONExporter{w: w}, nil
+	case ExportCSV:
+		return &csvExporter{w: csv.NewWriter(w)}, nil
+	case ExportKML:
+		return &kmlExporter{w: w}, nil
+	}
+	return nil, fmt.Errorf("format must be geojson, csv or kml")
+}
+
+// exportFields are the flat properties shared by every format, in column
+// order
+var exportFields = []string{
+	"id", "title", "description", "location", "neighbourhood", "categories", "tags",
+	"amenities", "price_band", "rating", "review_count", "download_mbps", "upload_mbps",
+	"latency_ms", "opening_hours", "image_url", "lat", "lng",
+}
+
+func exportValues(r HubExportRow) []string {
+	h := r.Hub
+	rating := ""
+	if r.Reviews > 0 {
+		rating = strconv.FormatFloat(roundTo(r.AvgRating, 2), 'f', -1, 64)
+	}
+	var down, up, latency string
+	if h.WiFi != nil {
+		down = strconv.FormatFloat(h.WiFi.DownloadMbps, 'f', -1, 64)
+		up = strconv.FormatFloat(h.WiFi.UploadMbps, 'f', -1, 64)
+		latency = strconv.FormatFloat(h.WiFi.LatencyMs, 'f', -1, 64)
+	}
+	var lat, lng string
+	if hasLocation(h) {
+		lat = strconv.FormatFloat(h.Coordinates.Lat, 'f', -1, 64)
+		lng = strconv.FormatFloat(h.Coordinates.Lng, 'f', -1, 64)
+	}
+	return []string{
+		h.ID.Hex(), h.Title, h.Description, h.LocationName, h.Neighbourhood,
+		strings.Join(h.Categories, ";"), strings.Join(h.Tags, ";"), strings.Join(h.Amenities, ";"),
+		h.PriceBand, rating, strconv.Itoa(r.Reviews), down, up, latency,
+		models.FormatOpeningHours(h.Hours), exportImageURL(h), lat, lng,
+	}
+}
+
+// exportImageURL is the cover image, which is stored first
+func exportImageURL(h models.Hub) string {
+	if len(h.Images) == 0 {
+		return ""
+	}
+	return h.Images[0].URL
+}
+
+// hasLocation treats 0,0 as "no location", as the map does
+func hasLocation(h models.Hub) bool {
+	return h.Coordinates.Lat != 0 || h.Coordinates.Lng != 0
+}
+
+func roundTo(x float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(x*p) / p
+}
+
+// ---------------- CSV ----------------
+
+type csvExporter struct {
+	w *csv.Writer
+}
+
+func (e *csvExporter) ContentType() string { return "text/csv; charset=utf-8" }
+func (e *csvExporter) Extension() string   { return "csv" }
+
+func (e *csvExporter) Begin() error {
+	return e.w.Write(exportFields)
+}
+
+func (e *csvExporter) Write(r HubExportRow) error {
+	values := exportValues(r)
+	for i, v := range values {
+		values[i] = csvSafe(v)
+	}
+	if err := e.w.Write(values); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExporter) End() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// csvSafe stops spreadsheets from running user text as a formula by
+// prefixing cells that start like one with a quote. Numbers such as a
+// negative latitude are left alone.
+func csvSafe(v string) string {
+	if v == "" || !strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return v
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return v
+	}
+	return "'" + v
+}
+
+// csvUnsafe undoes csvSafe, so exported cells import unchanged
+func csvUnsafe(v string) string {
+	if len(v) < 2 || v[0] != '\'' || csvSafe(v[1:]) != v {
+		return v
+	}
+	return v[1:]
+}
+
+// ---------------- GEOJSON ----------------
+
+// geoJSONExporter streams a FeatureCollection. Hubs without a location get
+// a null geometry, which GeoJSON allows.
+type geoJSONExporter struct {
+	w     io.Writer
+	count int
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id"`
+	Geometry   *geoJSONPoint          `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"` // lng, lat
+}
+
+func (e *geoJSONExporter) ContentType() string { return "application/geo+json" }
+func (e *geoJSONExporter) Extension() string   { return "geojson" }
+
+func (e *geoJSONExporter) Begin() error {
+	_, err := io.WriteString(e.w, `{"type":"FeatureCollection","features":[`)
+	return err
+}
+
+func (e *geoJSONExporter) Write(r HubExportRow) error {
+	h := r.Hub
+	f := geoJSONFeature{
+		Type: "Feature",
+		ID:   h.ID.Hex(),
+		Properties: map[string]interface{}{
+			"title":         h.Title,
+			"description":   h.Description,
+			"location":      h.LocationName,
+			"neighbourhood": h.Neighbourhood,
+			"categories":    nonNil(h.Categories),
+			"tags":          nonNil(h.Tags),
+			"amenities":     nonNil(h.Amenities),
+			"price_band":    h.PriceBand,
+			"rating":        nil,
+			"review_count":  r.Reviews,
+			"wifi":          h.WiFi,
+			"hours":         append([]models.OpeningPeriod{}, h.Hours...),
+			"opening_hours": models.FormatOpeningHours(h.Hours),
+			"image_url":     exportImageURL(h),
+		},
+	}
+	if r.Reviews > 0 {
+		f.Properties["rating"] = roundTo(r.AvgRating, 2)
+	}
+	if hasLocation(h) {
+		f.Geometry = &geoJSONPoint{Type: "Point", Coordinates: [2]float64{h.Coordinates.Lng, h.Coordinates.Lat}}
+	}
+
+	b, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.w.Write(b)
+	return err
+}
+
+func (e *geoJSONExporter) End() error {
+	_, err := io.WriteString(e.w, "]}\n")
+	return err
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// ---------------- KML ----------------
+
+// kmlExporter writes one Placemark per located hub, with every export
+// field in ExtendedData. KML has no way to place a hub without
+// coordinates, so those are skipped.
+type kmlExporter struct {
+	w io.Writer
+}
+
+func (e *kmlExporter) ContentType() string { return "application/vnd.google-earth.kml+xml" }
+func (e *kmlExporter) Extension() string   { return "kml" }
+
+func (e *kmlExporter) Begin() error {
+	_, err := io.WriteString(e.w, xml.Header+
+		`<kml xmlns="http://www.opengis.net/kml/2.2"><Document><name>Hubs</name>`+"\n")
+	return err
+}
+
+func (e *kmlExporter) Write(r HubExportRow) error {
+	if !hasLocation(r.Hub) {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString("<Placemark><name>")
+	xml.EscapeText(&b, []byte(r.Hub.Title))
+	b.WriteString("</name><description>")
+	xml.EscapeText(&b, []byte(r.Hub.Description))
+	b.WriteString("</description><ExtendedData>")
+	for i, v := range exportValues(r) {
+		if v == "" {
+			continue
+		}
+		fmt.Fprintf(&b, `<Data name="%s"><value>`, exportFields[i])
+		xml.EscapeText(&b, []byte(v))
+		b.WriteString("</value></Data>")
+	}
+	fmt.Fprintf(&b, "</ExtendedData><Point><coordinates>%s,%s</coordinates></Point></Placemark>\n",
+		strconv.FormatFloat(r.Hub.Coordinates.Lng, 'f', -1, 64),
+		strconv.FormatFloat(r.Hub.Coordinates.Lat, 'f', -1, 64))
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *kmlExporter) End() error {
+	_, err := io.WriteString(e.w, "</Document></kml>\n")
+	return err
+}
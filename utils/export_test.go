@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	models "github.com/phillip/contribution-tracker-go/models"
+)
+
+func TestCSVSafe(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"Java House", "Java House"},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+254 712 345678", "'+254 712 345678"},
+		{"-- closed --", "'-- closed --"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tcmd", "'\tcmd"},
+		{"-1.2625", "-1.2625"},
+		{"+36.8", "+36.8"},
+		{"1e5", "1e5"},
+		{"'quoted", "'quoted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := csvSafe(tt.in)
+			if got != tt.want {
+				t.Errorf("csvSafe(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if back := csvUnsafe(got); back != tt.in {
+				t.Errorf("csvUnsafe(%q) = %q, want %q", got, back, tt.in)
+			}
+		})
+	}
+}
+
+// exportFixture is a hub whose text needs escaping in every format
+func exportFixture() []HubExportRow {
+	return []HubExportRow{
+		{
+			Hub: models.Hub{
+				ID:           testID(1),
+				Title:        `=Tom & "Jerry's" <Café>`,
+				Description:  "Line one\nline two, with comma",
+				LocationName: "Westlands",
+				Categories:   []string{"cafe"},
+				Hours:        []models.OpeningPeriod{{Day: time.Monday, Open: "08:00", Close: "18:00"}},
+				Coordinates:  models.Coordinates{Lat: -1.2625, Lng: 36.8065},
+			},
+			AvgRating: 4.333,
+			Reviews:   3,
+		},
+		{Hub: models.Hub{ID: testID(2), Title: "No location"}},
+	}
+}
+
+func runExport(t *testing.T, format string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	e, err := NewHubExporter(format, &buf)
+	if err != nil {
+		t.Fatalf("NewHubExporter(%q) error = %v", format, err)
+	}
+	if err := e.Begin(); err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	for _, r := range exportFixture() {
+		if err := e.Write(r); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := e.End(); err != nil {
+		t.Fatalf("End() error = %v", err)
+	}
+	return buf.String()
+}
+
+func TestCSVExport(t *testing.T) {
+	want := "id,title,description,location,neighbourhood,categories,tags,amenities,price_band,rating,review_count,download_mbps,upload_mbps,latency_ms,opening_hours,image_url,lat,lng\n" +
+		"000000000000000000000001,\"'=Tom & \"\"Jerry's\"\" <Café>\",\"Line one\nline two, with comma\",Westlands,,cafe,,,,4.33,3,,,,Mon 08:00-18:00,,-1.2625,36.8065\n" +
+		"000000000000000000000002,No location,,,,,,,,,0,,,,,,,\n"
+	if got := runExport(t, ExportCSV); got != want {
+		t.Errorf("CSV export =\n%s\nwant\n%s", got, want)
+	}
+
+	rows, err := ParseHubImport(ImportCSV, bytes.NewBufferString(want))
+	if err != nil {
+		t.Fatalf("ParseHubImport(export) error = %v", err)
+	}
+	if rows[0].Title != exportFixture()[0].Hub.Title {
+		t.Errorf("re-imported title = %q, want %q", rows[0].Title, exportFixture()[0].Hub.Title)
+	}
+}
+
+func TestGeoJSONExport(t *testing.T) {
+	want := `{"type":"FeatureCollection","features":[` +
+		`{"type":"Feature","id":"000000000000000000000001","geometry":{"type":"Point","coordinates":[36.8065,-1.2625]},"properties":{"amenities":[],"categories":["cafe"],"description":"Line one\nline two, with comma","hours":[{"day":1,"open":"08:00","close":"18:00"}],"image_url":"","location":"Westlands","neighbourhood":"","opening_hours":"Mon 08:00-18:00","price_band":"","rating":4.33,"review_count":3,"tags":[],"title":"=Tom \u0026 \"Jerry's\" \u003cCafé\u003e","wifi":null}},` +
+		`{"type":"Feature","id":"000000000000000000000002","geometry":null,"properties":{"amenities":[],"categories":[],"description":"","hours":[],"image_url":"","location":"","neighbourhood":"","opening_hours":"","price_band":"","rating":null,"review_count":0,"tags":[],"title":"No location","wifi":null}}` +
+		"]}\n"
+	got := runExport(t, ExportGeoJSON)
+	if got != want {
+		t.Errorf("GeoJSON export =\n%s\nwant\n%s", got, want)
+	}
+
+	var fc struct {
+		Type     string
+		Features []struct {
+			Properties struct{ Title string }
+		}
+	}
+	if err := json.Unmarshal([]byte(got), &fc); err != nil {
+		t.Fatalf("GeoJSON export is not valid JSON: %v", err)
+	}
+	if fc.Type != "FeatureCollection" || len(fc.Features) != 2 || fc.Features[0].Properties.Title != exportFixture()[0].Hub.Title {
+		t.Errorf("decoded GeoJSON = %+v", fc)
+	}
+}
+
+func TestKMLExport(t *testing.T) {
+	want := xml.Header +
+		`<kml xmlns="http://www.opengis.net/kml/2.2"><Document><name>Hubs</name>` + "\n" +
+		`<Placemark><name>=Tom &amp; &#34;Jerry&#39;s&#34; &lt;Café&gt;</name><description>Line one&#xA;line two, with comma</description><ExtendedData>` +
+		`<Data name="id"><value>000000000000000000000001</value></Data>` +
+		`<Data name="title"><value>=Tom &amp; &#34;Jerry&#39;s&#34; &lt;Café&gt;</value></Data>` +
+		`<Data name="description"><value>Line one&#xA;line two, with comma</value></Data>` +
+		`<Data name="location"><value>Westlands</value></Data>` +
+		`<Data name="categories"><value>cafe</value></Data>` +
+		`<Data name="rating"><value>4.33</value></Data>` +
+		`<Data name="review_count"><value>3</value></Data>` +
+		`<Data name="opening_hours"><value>Mon 08:00-18:00</value></Data>` +
+		`<Data name="lat"><value>-1.2625</value></Data>` +
+		`<Data name="lng"><value>36.8065</value></Data>` +
+		`</ExtendedData><Point><coordinates>36.8065,-1.2625</coordinates></Point></Placemark>` + "\n" +
+		"</Document></kml>\n"
+	got := runExport(t, ExportKML)
+	if got != want {
+		t.Errorf("KML export =\n%s\nwant\n%s", got, want)
+	}
+
+	var doc struct {
+		Placemarks []struct {
+			Name string `xml:"name"`
+		} `xml:"Document>Placemark"`
+	}
+	if err := xml.Unmarshal([]byte(got), &doc); err != nil {
+		t.Fatalf("KML export is not valid XML: %v", err)
+	}
+	if len(doc.Placemarks) != 1 || doc.Placemarks[0].Name != exportFixture()[0].Hub.Title {
+		t.Errorf("decoded KML = %+v, want one placemark named %q", doc, exportFixture()[0].Hub.Title)
+	}
+}
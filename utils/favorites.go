@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"context"
+	"log"
+	"time"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BackfillFavoriteCounts sets favorites_count on hubs created before the
+// counter existed. Safe to run on every start.
+func BackfillFavoriteCounts(cfg *config.Config) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	ids, err := cfg.MongoClient.Database(cfg.DBName).Collection("hubs").
+		Distinct(ctx, "_id", bson.M{"favorites_count": bson.M{"$exists": false}})
+	if err != nil {
+		log.Printf("⚠️ Could not backfill favorite counts: %v", err)
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	hubIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if oid, ok := id.(primitive.ObjectID); ok {
+			hubIDs = append(hubIDs, oid)
+		}
+	}
+	if err := RecountFavorites(ctx, cfg, hubIDs...); err != nil {
+		log.Printf("⚠️ Could not backfill favorite counts: %v", err)
+		return
+	}
+	log.Printf("✅ Backfilled favorite counts on %d hubs", len(hubIDs))
+}
+
+// RecountFavorites recomputes favorites_count for the given hubs from the
+// favorites collection
+func RecountFavorites(ctx context.Context, cfg *config.Config, hubIDs ...primitive.ObjectID) error {
+	db := cfg.MongoClient.Database(cfg.DBName)
+
+	cursor, err := db.Collection("favorites").Aggregate(ctx, bson.A{
+		bson.M{"$match": bson.M{"hub_id": bson.M{"$in": hubIDs}}},
+		bson.M{"$group": bson.M{"_id": "$hub_id", "count": bson.M{"$sum": 1}}},
+	})
+	if err != nil {
+		return err
+	}
+	var counts []struct {
+		HubID primitive.ObjectID `bson:"_id"`
+		Count int                `bson:"count"`
+	}
+	if err := cursor.All(ctx, &counts); err != nil {
+		return err
+	}
+	byHub := map[primitive.ObjectID]int{}
+	for _, c := range counts {
+		byHub[c.HubID] = c.Count
+	}
+
+	hubCol := db.Collection("hubs")
+	for _, id := range hubIDs {
+		if _, err := hubCol.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"favorites_count": byHub[id]}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
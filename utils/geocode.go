@@ -0,0 +1,437 @@
+package utils
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/text/unicode/norm"
+)
+
+// The bundled boundaries are deliberately coarse: countries, cities and
+// neighbourhoods where hubs are listed today. GEO_BOUNDARIES_FILE swaps in
+// a fuller set in the same format.
+//
+//go:embed data/boundaries.geojson
+var defaultBoundaries []byte
+
+// Boundary levels, widest first
+const (
+	BoundaryCountry       = "country"
+	BoundaryCity          = "city"
+	BoundaryNeighbourhood = "neighbourhood"
+)
+
+// boundary is one named area. Rings are polygons as lng,lat pairs; the
+// first ring of each polygon is its outline, the rest are holes.
+type boundary struct {
+	Level    string
+	Name     string
+	Code     string // country code, countries only
+	TimeZone string
+	words    []string // names and aliases, as placeWords
+	slugs    []string // names and aliases, as NormalizeTag
+	polygons [][][][2]float64
+	minLng   float64
+	minLat   float64
+	maxLng   float64
+	maxLat   float64
+	area     float64 // in square degrees, to prefer the smallest match
+}
+
+var boundaries []boundary
+
+// InitGeocoder loads the boundaries used by ReverseGeocode
+func InitGeocoder(cfg *config.Config) error {
+	data := defaultBoundaries
+	if cfg.Geo.BoundariesFile != "" {
+		var err error
+		if data, err = os.ReadFile(cfg.Geo.BoundariesFile); err != nil {
+			return fmt.Errorf("reading boundaries: %w", err)
+		}
+	}
+	parsed, err := parseBoundaries(data)
+	if err != nil {
+		return err
+	}
+	boundaries = parsed
+	log.Printf("🗺️ Geocoder: %d boundaries", len(boundaries))
+	return nil
+}
+
+// ValidateCoordinates rejects out-of-range coordinates and 0,0, which is
+// what an unset form field decodes to
+func ValidateCoordinates(c models.Coordinates) error {
+	if math.IsNaN(c.Lat) || math.IsNaN(c.Lng) || c.Lat < -90 || c.Lat > 90 || c.Lng < -180 || c.Lng > 180 {
+		return fmt.Errorf("lat must be within -90..90 and lng within -180..180")
+	}
+	if c.Lat == 0 && c.Lng == 0 {
+		return fmt.Errorf("lat and lng are required")
+	}
+	return nil
+}
+
+// ReverseGeocode finds the smallest boundary at each level containing c,
+// and the time zone of the most specific one that has one. It returns nil
+// when no boundary contains c.
+func ReverseGeocode(c models.Coordinates) (*models.Address, string) {
+	var found [3]*boundary
+	for i := range boundaries {
+		b := &boundaries[i]
+		level := boundaryRank(b.Level)
+		if level < 0 || !b.contains(c) {
+			continue
+		}
+		if found[level] == nil || b.area < found[level].area {
+			found[level] = b
+		}
+	}
+	if found[0] == nil && found[1] == nil && found[2] == nil {
+		return nil, ""
+	}
+
+	addr := &models.Address{}
+	tz := ""
+	for _, b := range found {
+		if b == nil {
+			continue
+		}
+		switch b.Level {
+		case BoundaryCountry:
+			addr.Country, addr.CountryCode = b.Name, b.Code
+		case BoundaryCity:
+			addr.City = b.Name
+		case BoundaryNeighbourhood:
+			addr.Neighbourhood = b.Name
+		}
+		if b.TimeZone != "" {
+			tz = b.TimeZone
+		}
+	}
+	return addr, tz
+}
+
+// LocationMismatch reports whether a typed location names a known place
+// that doesn't contain c, e.g. "Westlands, Nairobi" for coordinates in
+// Karen. Places are compared level by level, so a name shared by several
+// places only needs one of them to agree. Typed text that names no known
+// place is never a mismatch.
+func LocationMismatch(typed string, c models.Coordinates) bool {
+	text := " " + strings.Join(placeWords(typed), " ") + " "
+	var named, agrees [3]bool
+	for i := range boundaries {
+		b := &boundaries[i]
+		level := boundaryRank(b.Level)
+		if level < 0 {
+			continue
+		}
+		for _, w := range b.words {
+			if namesPlace(text, w) {
+				named[level] = true
+				agrees[level] = agrees[level] || b.contains(c)
+				break
+			}
+		}
+	}
+	for level := range named {
+		if named[level] && !agrees[level] {
+			return true
+		}
+	}
+	return false
+}
+
+// NeighbourhoodMismatch reports whether a taxonomy neighbourhood slug
+// matches the name or an alias of a neighbourhood boundary, and no such
+// boundary contains c. Slugs no boundary knows are never a mismatch.
+func NeighbourhoodMismatch(slug string, c models.Coordinates) bool {
+	if slug == "" {
+		return false
+	}
+	known := false
+	for i := range boundaries {
+		b := &boundaries[i]
+		if b.Level != BoundaryNeighbourhood || !slices.Contains(b.slugs, slug) {
+			continue
+		}
+		if b.contains(c) {
+			return false
+		}
+		known = true
+	}
+	return known
+}
+
+// GeocodeHub fills the hub's address, time zone and mismatch flags from
+// its coordinates, typed location and taxonomy neighbourhood
+func GeocodeHub(h *models.Hub) {
+	h.Address, h.TimeZone = ReverseGeocode(h.Coordinates)
+	h.LocationMismatch = LocationMismatch(h.LocationName, h.Coordinates)
+	h.NeighbourhoodMismatch = NeighbourhoodMismatch(h.Neighbourhood, h.Coordinates)
+}
+
+// BackfillHubAddresses geocodes located hubs that have no address yet,
+// and flags hubs whose taxonomy neighbourhood the coordinates are not in.
+// Safe to run on every start.
+func BackfillHubAddresses(cfg *config.Config) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	col := cfg.MongoClient.Database(cfg.DBName).Collection("hubs")
+	cursor, err := col.Find(ctx, bson.M{
+		"$or": bson.A{
+			bson.M{"address": bson.M{"$exists": false}},
+			bson.M{"neighbourhood": bson.M{"$nin": bson.A{nil, ""}}, "neighbourhood_mismatch": bson.M{"$ne": true}},
+		},
+		"merged_into": bson.M{"$exists": false},
+		"$nor":        bson.A{bson.M{"coordinates.lat": 0, "coordinates.lng": 0}},
+	}, options.Find().SetProjection(bson.M{"coordinates": 1, "location": 1, "timezone": 1, "neighbourhood": 1, "address": 1}))
+	if err != nil {
+		log.Printf("⚠️ Could not backfill hub addresses: %v", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	n := 0
+	for cursor.Next(ctx) {
+		var hub models.Hub
+		if err := cursor.Decode(&hub); err != nil {
+			continue
+		}
+		if hub.Address != nil {
+			// already geocoded; only the neighbourhood needs checking
+			if !NeighbourhoodMismatch(hub.Neighbourhood, hub.Coordinates) {
+				continue
+			}
+			if _, err := col.UpdateByID(ctx, hub.ID, bson.M{"$set": bson.M{"neighbourhood_mismatch": true}}); err != nil {
+				log.Printf("⚠️ Could not flag neighbourhood of hub %s: %v", hub.ID.Hex(), err)
+				continue
+			}
+			n++
+			continue
+		}
+		typedTZ := hub.TimeZone
+		GeocodeHub(&hub)
+		if hub.Address == nil {
+			continue // outside every boundary; looked at again next start
+		}
+		if hub.TimeZone == "" {
+			hub.TimeZone = typedTZ
+		}
+		_, err := col.UpdateByID(ctx, hub.ID, bson.M{"$set": bson.M{
+			"address":                hub.Address,
+			"timezone":               hub.TimeZone,
+			"location_mismatch":      hub.LocationMismatch,
+			"neighbourhood_mismatch": hub.NeighbourhoodMismatch,
+		}})
+		if err != nil {
+			log.Printf("⚠️ Could not save address of hub %s: %v", hub.ID.Hex(), err)
+			continue
+		}
+		n++
+	}
+	if n > 0 {
+		log.Printf("✅ Backfilled addresses or neighbourhood flags on %d hubs", n)
+	}
+}
+
+// =============================
+// Helpers
+// =============================
+
+func boundaryRank(level string) int {
+	switch level {
+	case BoundaryCountry:
+		return 0
+	case BoundaryCity:
+		return 1
+	case BoundaryNeighbourhood:
+		return 2
+	}
+	return -1
+}
+
+// parseBoundaries reads a FeatureCollection of Polygon and MultiPolygon
+// features with level, name and optional code, timezone and aliases
+// properties
+func parseBoundaries(data []byte) ([]boundary, error) {
+	var fc struct {
+		Features []struct {
+			Properties struct {
+				Level    string   `json:"level"`
+				Name     string   `json:"name"`
+				Code     string   `json:"code"`
+				TimeZone string   `json:"timezone"`
+				Aliases  []string `json:"aliases"`
+			} `json:"properties"`
+			Geometry struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("reading boundaries: %w", err)
+	}
+
+	out := make([]boundary, 0, len(fc.Features))
+	for i, f := range fc.Features {
+		p := f.Properties
+		if boundaryRank(p.Level) < 0 || p.Name == "" {
+			return nil, fmt.Errorf("boundary %d: needs a name and a level of country, city or neighbourhood", i)
+		}
+		if p.TimeZone != "" {
+			if _, err := time.LoadLocation(p.TimeZone); err != nil {
+				return nil, fmt.Errorf("boundary %q: %w", p.Name, err)
+			}
+		}
+
+		var polygons [][][][2]float64
+		switch f.Geometry.Type {
+		case "Polygon":
+			var poly [][][2]float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &poly); err != nil {
+				return nil, fmt.Errorf("boundary %q: %w", p.Name, err)
+			}
+			polygons = [][][][2]float64{poly}
+		case "MultiPolygon":
+			if err := json.Unmarshal(f.Geometry.Coordinates, &polygons); err != nil {
+				return nil, fmt.Errorf("boundary %q: %w", p.Name, err)
+			}
+		default:
+			return nil, fmt.Errorf("boundary %q: geometry must be a Polygon or MultiPolygon", p.Name)
+		}
+
+		b := boundary{
+			Level:    p.Level,
+			Name:     p.Name,
+			Code:     strings.ToUpper(p.Code),
+			TimeZone: p.TimeZone,
+			polygons: polygons,
+			minLng:   math.Inf(1),
+			minLat:   math.Inf(1),
+			maxLng:   math.Inf(-1),
+			maxLat:   math.Inf(-1),
+		}
+		for _, name := range append([]string{p.Name}, p.Aliases...) {
+			if w := strings.Join(placeWords(name), " "); w != "" {
+				b.words = append(b.words, w)
+			}
+			if slug := NormalizeTag(name); slug != "" {
+				b.slugs = append(b.slugs, slug)
+			}
+		}
+		for _, poly := range polygons {
+			for r, ring := range poly {
+				a := math.Abs(ringArea(ring))
+				if r == 0 {
+					b.area += a
+				} else {
+					b.area -= a
+				}
+				for _, pt := range ring {
+					b.minLng, b.maxLng = math.Min(b.minLng, pt[0]), math.Max(b.maxLng, pt[0])
+					b.minLat, b.maxLat = math.Min(b.minLat, pt[1]), math.Max(b.maxLat, pt[1])
+				}
+			}
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+func (b *boundary) contains(c models.Coordinates) bool {
+	if c.Lng < b.minLng || c.Lng > b.maxLng || c.Lat < b.minLat || c.Lat > b.maxLat {
+		return false
+	}
+	for _, poly := range b.polygons {
+		if len(poly) == 0 || !ringContains(poly[0], c) {
+			continue
+		}
+		inHole := false
+		for _, hole := range poly[1:] {
+			if ringContains(hole, c) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// ringContains is the even-odd ray casting test
+func ringContains(ring [][2]float64, c models.Coordinates) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > c.Lat) != (yj > c.Lat) && c.Lng < (xj-xi)*(c.Lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// ringArea is the shoelace area of a ring
+func ringArea(ring [][2]float64) float64 {
+	a := 0.0
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a += (ring[j][0] + ring[i][0]) * (ring[j][1] - ring[i][1])
+	}
+	return a / 2
+}
+
+// streetWords follow place names that are used as street names, as in
+// "Mombasa Road" in Nairobi
+var streetWords = map[string]bool{
+	"road": true, "rd": true, "street": true, "st": true, "avenue": true, "ave": true,
+	"highway": true, "hwy": true, "way": true, "drive": true, "lane": true, "close": true,
+}
+
+// namesPlace reports whether text, as space-padded placeWords, mentions
+// the place name w other than as part of a street name
+func namesPlace(text, w string) bool {
+	needle := " " + w + " "
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], needle)
+		if i < 0 {
+			return false
+		}
+		rest := strings.Fields(text[offset+i+len(needle):])
+		if len(rest) == 0 || !streetWords[rest[0]] {
+			return true
+		}
+		offset += i + len(needle) - 1
+	}
+}
+
+// placeWords lowercases s, drops accents and splits it into words, so
+// "Nairobi CBD," and "nairobi cbd" compare equal
+func placeWords(s string) []string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteByte(' ')
+		}
+	}
+	return strings.Fields(b.String())
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"math"
+	"testing"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+)
+
+var (
+	westlands = models.Coordinates{Lat: -1.2625, Lng: 36.8065}
+	karen     = models.Coordinates{Lat: -1.335, Lng: 36.705}
+	nyali     = models.Coordinates{Lat: -4.025, Lng: 39.715}
+	kampala   = models.Coordinates{Lat: 0.315, Lng: 32.59}
+	kigali    = models.Coordinates{Lat: -1.975, Lng: 30.085}
+	ocean     = models.Coordinates{Lat: 0, Lng: -20}
+)
+
+func initTestGeocoder(t *testing.T) {
+	t.Helper()
+	if err := InitGeocoder(&config.Config{}); err != nil {
+		t.Fatalf("InitGeocoder() = %v", err)
+	}
+}
+
+func TestValidateCoordinates(t *testing.T) {
+	tests := []struct {
+		name string
+		c    models.Coordinates
+		ok   bool
+	}{
+		{"nairobi", westlands, true},
+		{"unset", models.Coordinates{}, false},
+		{"on the equator", models.Coordinates{Lat: 0, Lng: 32.59}, true},
+		{"lat out of range", models.Coordinates{Lat: 91, Lng: 36.8}, false},
+		{"lng out of range", models.Coordinates{Lat: -1.2, Lng: -181}, false},
+		{"not a number", models.Coordinates{Lat: math.NaN(), Lng: 36.8}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCoordinates(tt.c); (err == nil) != tt.ok {
+				t.Errorf("ValidateCoordinates(%+v) = %v, want ok %v", tt.c, err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestReverseGeocode(t *testing.T) {
+	initTestGeocoder(t)
+	tests := []struct {
+		name string
+		c    models.Coordinates
+		want *models.Address
+		tz   string
+	}{
+		{"westlands", westlands, &models.Address{Country: "Kenya", CountryCode: "KE", City: "Nairobi", Neighbourhood: "Westlands"}, "Africa/Nairobi"},
+		{"karen", karen, &models.Address{Country: "Kenya", CountryCode: "KE", City: "Nairobi", Neighbourhood: "Karen"}, "Africa/Nairobi"},
+		{"nyali", nyali, &models.Address{Country: "Kenya", CountryCode: "KE", City: "Mombasa", Neighbourhood: "Nyali"}, "Africa/Nairobi"},
+		{"kampala", kampala, &models.Address{Country: "Uganda", CountryCode: "UG", City: "Kampala"}, "Africa/Kampala"},
+		{"kigali", kigali, &models.Address{Country: "Rwanda", CountryCode: "RW", City: "Kigali"}, "Africa/Kigali"},
+		{"ocean", ocean, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, tz := ReverseGeocode(tt.c)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) || tz != tt.tz {
+				t.Errorf("ReverseGeocode(%+v) = %+v, %q; want %+v, %q", tt.c, got, tz, tt.want, tt.tz)
+			}
+		})
+	}
+}
+
+func TestLocationMismatch(t *testing.T) {
+	initTestGeocoder(t)
+	tests := []struct {
+		name  string
+		typed string
+		c     models.Coordinates
+		want  bool
+	}{
+		{"matching neighbourhood", "Westlands, Nairobi", westlands, false},
+		{"wrong neighbourhood", "Westlands, Nairobi", karen, true},
+		{"wrong city", "Nyali, Mombasa", westlands, true},
+		{"wrong country", "Kigali", kampala, true},
+		{"case and accents", "WÉSTLANDS", westlands, false},
+		{"alias", "Upperhill, Nairobi", nyali, true},
+		{"street named after a place", "Karen Road, Westlands", westlands, false},
+		{"place and street", "Karen, off Karen Road", westlands, true},
+		{"no known place", "Second floor, Sarit", karen, false},
+		{"empty", "", karen, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LocationMismatch(tt.typed, tt.c); got != tt.want {
+				t.Errorf("LocationMismatch(%q) = %v, want %v", tt.typed, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNeighbourhoodMismatch(t *testing.T) {
+	initTestGeocoder(t)
+	tests := []struct {
+		name string
+		slug string
+		c    models.Coordinates
+		want bool
+	}{
+		{"matching neighbourhood", "westlands", westlands, false},
+		{"wrong neighbourhood", "westlands", karen, true},
+		{"alias", "upperhill", westlands, true},
+		{"outside every neighbourhood", "karen", kampala, true},
+		{"no boundary for the slug", "sarit", karen, false},
+		{"unset", "", karen, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeighbourhoodMismatch(tt.slug, tt.c); got != tt.want {
+				t.Errorf("NeighbourhoodMismatch(%q) = %v, want %v", tt.slug, got, tt.want)
+			}
+		})
+	}
+}
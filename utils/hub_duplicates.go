@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// DuplicateRadiusMeters is how far apart two listings of one place may be
+	DuplicateRadiusMeters = 250.0
+	// DuplicateMinSimilarity is the title similarity (0..1) above which
+	// nearby hubs are reported as likely duplicates
+	DuplicateMinSimilarity = 0.6
+
+	earthRadiusMeters = 6371000.0
+)
+
+// DuplicateCandidate is an existing hub that looks like the one being created
+type DuplicateCandidate struct {
+	ID             primitive.ObjectID `json:"id"`
+	Title          string             `json:"title"`
+	LocationName   string             `json:"location_name,omitempty"`
+	Coordinates    models.Coordinates `json:"coordinates"`
+	DistanceMeters float64            `json:"distance_meters"`
+	Similarity     float64            `json:"similarity"`
+}
+
+// titleNoise are words that don't tell two venues apart
+var titleNoise = map[string]bool{
+	"the": true, "a": true, "and": true, "&": true, "of": true,
+	"cafe": true, "coffee": true, "restaurant": true, "hub": true,
+	"ltd": true, "limited": true, "co": true,
+}
+
+// FindDuplicateHubs returns existing, unmerged hubs within
+// DuplicateRadiusMeters of coords whose titles resemble title, most similar
+// first. Hubs without coordinates are never matched.
+func FindDuplicateHubs(ctx context.Context, cfg *config.Config, title string, coords models.Coordinates, exclude primitive.ObjectID) ([]DuplicateCandidate, error) {
+	if coords.Lat == 0 && coords.Lng == 0 {
+		return nil, nil
+	}
+
+	// bounding box prefilter, refined with the haversine distance below
+	filter := NearbyFilter(coords, DuplicateRadiusMeters)
+	filter["merged_into"] = bson.M{"$exists": false}
+	if !exclude.IsZero() {
+		filter["_id"] = bson.M{"$ne": exclude}
+	}
+
+	cursor, err := cfg.MongoClient.Database(cfg.DBName).Collection("hubs").Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	var hubs []models.Hub
+	if err := cursor.All(ctx, &hubs); err != nil {
+		return nil, err
+	}
+
+	candidates := []DuplicateCandidate{}
+	for _, h := range hubs {
+		dist := HaversineMeters(coords, h.Coordinates)
+		if dist > DuplicateRadiusMeters {
+			continue
+		}
+		sim := TitleSimilarity(title, h.Title)
+		if sim < DuplicateMinSimilarity {
+			continue
+		}
+		candidates = append(candidates, DuplicateCandidate{
+			ID:             h.ID,
+			Title:          h.Title,
+			LocationName:   h.LocationName,
+			Coordinates:    h.Coordinates,
+			DistanceMeters: math.Round(dist),
+			Similarity:     math.Round(sim*100) / 100,
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Similarity != candidates[j].Similarity {
+			return candidates[i].Similarity > candidates[j].Similarity
+		}
+		return candidates[i].DistanceMeters < candidates[j].DistanceMeters
+	})
+	return candidates, nil
+}
+
+// NearbyFilter matches hubs inside the bounding box of a circle of
+// radiusMeters around c. Callers refine with HaversineMeters if they need
+// the circle itself.
+func NearbyFilter(c models.Coordinates, radiusMeters float64) bson.M {
+	dLat := radiusMeters / earthRadiusMeters * 180 / math.Pi
+	dLng := dLat / math.Max(math.Cos(c.Lat*math.Pi/180), 0.01)
+	return bson.M{
+		"coordinates.lat": bson.M{"$gte": c.Lat - dLat, "$lte": c.Lat + dLat},
+		"coordinates.lng": bson.M{"$gte": c.Lng - dLng, "$lte": c.Lng + dLng},
+	}
+}
+
+// HaversineMeters returns the great-circle distance between two points
+func HaversineMeters(a, b models.Coordinates) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := lat2 - lat1
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// TitleSimilarity scores two venue names from 0 to 1. It takes the better of
+// an edit-distance ratio (typos) and word overlap (reordered or extra words),
+// after dropping case, punctuation and generic words like "cafe".
+func TitleSimilarity(a, b string) float64 {
+	ta, tb := titleTokens(a), titleTokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	sa, sb := strings.Join(ta, " "), strings.Join(tb, " ")
+	if sa == sb {
+		return 1
+	}
+	ra, rb := []rune(sa), []rune(sb)
+	edit := 1 - float64(levenshtein(ra, rb))/float64(max(len(ra), len(rb)))
+
+	set := map[string]bool{}
+	for _, t := range ta {
+		set[t] = true
+	}
+	shared := 0
+	for _, t := range tb {
+		if set[t] {
+			shared++
+			delete(set, t)
+		}
+	}
+	overlap := float64(shared) / float64(min(len(ta), len(tb)))
+	// a single shared word between long names is weak evidence
+	if min(len(ta), len(tb)) == 1 && max(len(ta), len(tb)) > 2 {
+		overlap *= 0.75
+	}
+
+	return math.Max(edit, overlap)
+}
+
+// titleTokens lowercases s, drops accents and punctuation and drops noise
+// words. If nothing but noise is left, the noise words are kept.
+func titleTokens(s string) []string {
+	var folded strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		if !unicode.Is(unicode.Mn, r) {
+			folded.WriteRune(r)
+		}
+	}
+	words := strings.FieldsFunc(folded.String(), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&'
+	})
+	tokens := []string{}
+	for _, w := range words {
+		if !titleNoise[w] {
+			tokens = append(tokens, w)
+		}
+	}
+	if len(tokens) == 0 {
+		return words
+	}
+	return tokens
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"math"
+	"testing"
+
+	models "github.com/phillip/contribution-tracker-go/models"
+)
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      string
+		want      float64
+		duplicate bool // at or above DuplicateMinSimilarity
+	}{
+		{"identical", "Java House", "Java House", 1, true},
+		{"casing", "JAVA house", "java House", 1, true},
+		{"punctuation", "Java-House!", "Java House", 1, true},
+		{"accents", "Kahawa Tamú", "Kahawa Tamu", 1, true},
+		{"noise words", "The Java House Cafe", "Java House", 1, true},
+		{"typo", "Jaba House", "Java House", 0.9, true},
+		{"reordered", "House of Java", "Java House", 1, true},
+		{"extra word", "Java House Westlands", "Java House", 1, true},
+		{"one shared word", "Nairobi Garage Westlands", "Nairobi", 0.75, true},
+		{"near miss", "Java House", "Jamia House", 0.818, true},
+		{"other branch", "Java House Westlands", "Java House Kilimani", 0.667, true}, // told apart by distance
+		{"different places", "Java House", "Artcaffe", 0.2, false},
+		{"only noise words", "The Cafe", "Cafe", 1, true},
+		{"empty", "", "Java House", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := math.Round(TitleSimilarity(tt.a, tt.b)*1000) / 1000
+			if got != tt.want {
+				t.Errorf("TitleSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if dup := got >= DuplicateMinSimilarity; dup != tt.duplicate {
+				t.Errorf("TitleSimilarity(%q, %q) duplicate = %v, want %v", tt.a, tt.b, dup, tt.duplicate)
+			}
+			if back := math.Round(TitleSimilarity(tt.b, tt.a)*1000) / 1000; back != got {
+				t.Errorf("TitleSimilarity is not symmetric: %v vs %v", got, back)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "java", 4},
+		{"java", "java", 0},
+		{"java", "jaba", 1},
+		{"kitten", "sitting", 3},
+		{"tamú", "tamu", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestHaversineMeters(t *testing.T) {
+	westlands := models.Coordinates{Lat: -1.2625, Lng: 36.8065}
+	tests := []struct {
+		name string
+		b    models.Coordinates
+		want float64 // meters, to the nearest 10
+	}{
+		{"same point", westlands, 0},
+		{"0.001° of latitude", models.Coordinates{Lat: -1.2635, Lng: 36.8065}, 110},
+		{"Westlands to Karen", models.Coordinates{Lat: -1.335, Lng: 36.705}, 13870},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := math.Round(HaversineMeters(westlands, tt.b)/10) * 10; got != tt.want {
+				t.Errorf("HaversineMeters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package utils
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Hub import formats; CSV columns and GeoJSON properties use the names the
+// export writes, so an export can be edited and imported again
+const (
+	ImportCSV     = ExportCSV
+	ImportGeoJSON = ExportGeoJSON
+
+	// MaxImportRows caps the rows of one import file
+	MaxImportRows = 5000
+)
+
+// HubImportRow is one parsed, not yet validated hub. Lat and Lng are nil
+// when the row has no location. Errors holds values that couldn't be read.
+type HubImportRow struct {
+	Row           int
+	Title         string
+	Description   string
+	LocationName  string
+	Neighbourhood string
+	Categories    []string
+	Tags          []string
+	Amenities     []string
+	PriceBand     string
+	OpeningHours  string
+	Lat, Lng      *float64
+	Errors        []string
+}
+
+// importColumns maps accepted CSV headers to the field they fill
+var importColumns = map[string]string{
+	"title":         "title",
+	"name":          "title",
+	"description":   "description",
+	"location":      "location",
+	"location_name": "location",
+	"neighbourhood": "neighbourhood",
+	"neighborhood":  "neighbourhood",
+	"categories":    "categories",
+	"tags":          "tags",
+	"amenities":     "amenities",
+	"price_band":    "price_band",
+	"opening_hours": "opening_hours",
+	"hours":         "opening_hours",
+	"lat":           "lat",
+	"latitude":      "lat",
+	"lng":           "lng",
+	"lon":           "lng",
+	"longitude":     "lng",
+}
+
+// ImportFormat picks the format from ?format= or else the file extension
+func ImportFormat(format, filename string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".csv":
+			format = ImportCSV
+		case ".geojson", ".json":
+			format = ImportGeoJSON
+		}
+	}
+	if format != ImportCSV && format != ImportGeoJSON {
+		return "", fmt.Errorf("format must be csv or geojson")
+	}
+	return format, nil
+}
+
+// ParseHubImport reads every row of an import file. It fails only when the
+// file as a whole can't be read; problems with single rows are left in
+// HubImportRow.Errors.
+func ParseHubImport(format string, r io.Reader) ([]HubImportRow, error) {
+	var (
+		rows []HubImportRow
+		err  error
+	)
+	switch format {
+	case ImportCSV:
+		rows, err = parseImportCSV(r)
+	case ImportGeoJSON:
+		rows, err = parseImportGeoJSON(r)
+	default:
+		return nil, fmt.Errorf("format must be csv or geojson")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the file has no hubs")
+	}
+	if len(rows) > MaxImportRows {
+		return nil, fmt.Errorf("the file has %d hubs; split it into files of at most %d", len(rows), MaxImportRows)
+	}
+	return rows, nil
+}
+
+func parseImportCSV(r io.Reader) ([]HubImportRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read the CSV header: %v", err)
+	}
+	columns := make([]string, len(header))
+	hasTitle := false
+	for i, h := range header {
+		columns[i] = importColumns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))]
+		hasTitle = hasTitle || columns[i] == "title"
+	}
+	if !hasTitle {
+		return nil, fmt.Errorf("the CSV needs a title column")
+	}
+
+	rows := []HubImportRow{}
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read CSV row %d: %v", line, err)
+		}
+		if len(rows) >= MaxImportRows {
+			return nil, fmt.Errorf("the file has more than %d hubs; split it up", MaxImportRows)
+		}
+		values := map[string]string{}
+		for i, v := range record {
+			if i < len(columns) && columns[i] != "" {
+				values[columns[i]] = strings.TrimSpace(csvUnsafe(v))
+			}
+		}
+		row := importRow(line, values)
+		row.Lat = parseImportCoord(&row, "lat", values["lat"])
+		row.Lng = parseImportCoord(&row, "lng", values["lng"])
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseImportGeoJSON(r io.Reader) ([]HubImportRow, error) {
+	var fc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry *struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"` // nested deeper for non-Points
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.NewDecoder(r).Decode(&fc); err != nil {
+		return nil, fmt.Errorf("could not read the GeoJSON: %v", err)
+	}
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("the GeoJSON must be a FeatureCollection")
+	}
+	if len(fc.Features) > MaxImportRows {
+		return nil, fmt.Errorf("the file has %d hubs; split it into files of at most %d", len(fc.Features), MaxImportRows)
+	}
+
+	rows := make([]HubImportRow, 0, len(fc.Features))
+	for i, f := range fc.Features {
+		values := map[string]string{}
+		for k, v := range f.Properties {
+			field := importColumns[strings.ToLower(k)]
+			if field == "" || field == "lat" || field == "lng" {
+				continue
+			}
+			values[field] = geoJSONValue(v)
+		}
+		row := importRow(i+1, values)
+		if f.Geometry != nil {
+			var point []float64
+			if f.Geometry.Type != "Point" || json.Unmarshal(f.Geometry.Coordinates, &point) != nil || len(point) < 2 {
+				row.Errors = append(row.Errors, "geometry must be a Point")
+			} else {
+				lng, lat := point[0], point[1]
+				row.Lat, row.Lng = &lat, &lng
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// importRow fills the text fields shared by both formats
+func importRow(n int, values map[string]string) HubImportRow {
+	return HubImportRow{
+		Row:           n,
+		Title:         values["title"],
+		Description:   values["description"],
+		LocationName:  values["location"],
+		Neighbourhood: values["neighbourhood"],
+		Categories:    splitImportList(values["categories"]),
+		Tags:          splitImportList(values["tags"]),
+		Amenities:     splitImportList(values["amenities"]),
+		PriceBand:     strings.ToLower(values["price_band"]),
+		OpeningHours:  values["opening_hours"],
+	}
+}
+
+func parseImportCoord(row *HubImportRow, name, v string) *float64 {
+	if v == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		row.Errors = append(row.Errors, fmt.Sprintf("%s %q is not a number", name, v))
+		return nil
+	}
+	return &f
+}
+
+// geoJSONValue flattens a property; arrays become ";"-separated lists
+func geoJSONValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(t)
+	case []interface{}:
+		parts := make([]string, 0, len(t))
+		for _, item := range t {
+			parts = append(parts, geoJSONValue(item))
+		}
+		return strings.Join(parts, ";")
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// splitImportList splits on ";" (as exported) or ","
+func splitImportList(v string) []string {
+	out := []string{}
+	for _, part := range strings.FieldsFunc(v, func(r rune) bool { return r == ';' || r == ',' }) {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// FailInterruptedImports marks imports that were queued or running when
+// the server last stopped as failed, since nothing will resume them
+func FailInterruptedImports(cfg *config.Config) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := cfg.MongoClient.Database(cfg.DBName).Collection("hub_imports").UpdateMany(ctx,
+		bson.M{"status": bson.M{"$in": bson.A{models.ImportStatusQueued, models.ImportStatusRunning}}},
+		bson.M{"$set": bson.M{
+			"status":      models.ImportStatusFailed,
+			"error":       "interrupted by a server restart; rows already reported were imported",
+			"finished_at": time.Now(),
+		}})
+	if err != nil {
+		log.Printf("⚠️ Could not clean up interrupted hub imports: %v", err)
+		return
+	}
+	if res.ModifiedCount > 0 {
+		log.Printf("⚠️ Marked %d interrupted hub imports as failed", res.ModifiedCount)
+	}
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseHubImportCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    HubImportRow
+		wantErr string
+	}{
+		{
+			name: "export headers",
+			csv:  "title,description,location_name,categories,tags,price_band,opening_hours,lat,lng\nJava House,Good coffee,Westlands,cafe;cowork,quiet,Low,Mon 08:00-18:00,-1.2625,36.8065\n",
+			want: HubImportRow{
+				Row: 1, Title: "Java House", Description: "Good coffee", LocationName: "Westlands",
+				Categories: []string{"cafe", "cowork"}, Tags: []string{"quiet"}, Amenities: []string{},
+				PriceBand: "low", OpeningHours: "Mon 08:00-18:00", Lat: floatPtr(-1.2625), Lng: floatPtr(36.8065),
+			},
+		},
+		{
+			name: "aliases, BOM and unknown columns",
+			csv:  "\ufeffName,Neighborhood,Latitude,Lon,notes\n Java House , Westlands ,-1.2625,36.8065,ignored\n",
+			want: HubImportRow{
+				Row: 1, Title: "Java House", Neighbourhood: "Westlands",
+				Categories: []string{}, Tags: []string{}, Amenities: []string{},
+				Lat: floatPtr(-1.2625), Lng: floatPtr(36.8065),
+			},
+		},
+		{
+			name: "exported formula cells come back unchanged",
+			csv:  "title,description,lat\n\"'=HYPERLINK(\"\"x\"\")\",'-- closed on Sundays --,-1.5\n",
+			want: HubImportRow{
+				Row: 1, Title: `=HYPERLINK("x")`, Description: "-- closed on Sundays --",
+				Categories: []string{}, Tags: []string{}, Amenities: []string{}, Lat: floatPtr(-1.5),
+			},
+		},
+		{
+			name: "bad coordinate is a row error",
+			csv:  "title,lat\nJava House,north\n",
+			want: HubImportRow{
+				Row: 1, Title: "Java House", Categories: []string{}, Tags: []string{}, Amenities: []string{},
+				Errors: []string{`lat "north" is not a number`},
+			},
+		},
+		{name: "no title column", csv: "description,lat\nGood coffee,-1.2\n", wantErr: "title column"},
+		{name: "header only", csv: "title\n", wantErr: "no hubs"},
+		{name: "empty file", csv: "", wantErr: "CSV header"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseHubImport(ImportCSV, strings.NewReader(tt.csv))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseHubImport() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseHubImport() error = %v", err)
+			}
+			if len(rows) != 1 || !reflect.DeepEqual(rows[0], tt.want) {
+				t.Errorf("ParseHubImport() = %+v, want [%+v]", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHubImportGeoJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		geometry string
+		lat, lng *float64
+		errors   []string
+	}{
+		{"point", `{"type":"Point","coordinates":[36.8065,-1.2625]}`, floatPtr(-1.2625), floatPtr(36.8065), nil},
+		{"no geometry", `null`, nil, nil, nil},
+		{"polygon", `{"type":"Polygon","coordinates":[[[36.8,-1.2],[36.9,-1.2],[36.9,-1.3],[36.8,-1.2]]]}`, nil, nil, []string{"geometry must be a Point"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := fmt.Sprintf(`{"type":"FeatureCollection","features":[{"type":"Feature","geometry":%s,"properties":{"title":"Java House","tags":["quiet","outlets"],"lat":5}}]}`, tt.geometry)
+			rows, err := ParseHubImport(ImportGeoJSON, strings.NewReader(doc))
+			if err != nil {
+				t.Fatalf("ParseHubImport() error = %v", err)
+			}
+			row := rows[0]
+			if row.Title != "Java House" || !reflect.DeepEqual(row.Tags, []string{"quiet", "outlets"}) {
+				t.Errorf("properties = %q, %q", row.Title, row.Tags)
+			}
+			if !reflect.DeepEqual(row.Lat, tt.lat) || !reflect.DeepEqual(row.Lng, tt.lng) || !reflect.DeepEqual(row.Errors, tt.errors) {
+				t.Errorf("location = %v, %v, %q; want %v, %v, %q", row.Lat, row.Lng, row.Errors, tt.lat, tt.lng, tt.errors)
+			}
+		})
+	}
+
+	if _, err := ParseHubImport(ImportGeoJSON, strings.NewReader(`{"type":"Feature"}`)); err == nil {
+		t.Errorf("ParseHubImport(Feature) = nil error, want FeatureCollection error")
+	}
+}
+
+func TestParseHubImportRowLimit(t *testing.T) {
+	csv := "title\n" + strings.Repeat("Java House\n", MaxImportRows+1)
+	if _, err := ParseHubImport(ImportCSV, strings.NewReader(csv)); err == nil || !strings.Contains(err.Error(), "split it up") {
+		t.Errorf("ParseHubImport(CSV) error = %v, want the row limit", err)
+	}
+
+	features := strings.TrimSuffix(strings.Repeat(`{"type":"Feature","geometry":null,"properties":{"title":"Java House"}},`, MaxImportRows+1), ",")
+	doc := `{"type":"FeatureCollection","features":[` + features + `]}`
+	if _, err := ParseHubImport(ImportGeoJSON, strings.NewReader(doc)); err == nil || !strings.Contains(err.Error(), "at most") {
+		t.Errorf("ParseHubImport(GeoJSON) error = %v, want the row limit", err)
+	}
+
+	rows, err := ParseHubImport(ImportCSV, strings.NewReader("title\n"+strings.Repeat("Java House\n", MaxImportRows)))
+	if err != nil || len(rows) != MaxImportRows {
+		t.Errorf("ParseHubImport() at the limit = %d rows, %v", len(rows), err)
+	}
+}
+
+func floatPtr(f float64) *float64 { return &f }
@@ -0,0 +1,247 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+
+	"github.com/buckket/go-blurhash"
+	"github.com/gen2brain/heic"
+	"github.com/gen2brain/webp"
+	"golang.org/x/image/draw"
+)
+
+// maxImagePixels rejects decompression bombs before decoding
+const maxImagePixels = 50_000_000
+
+// ImageSize is a standard rendition, fitted within MaxDim on its longest side
+type ImageSize struct {
+	Name   string
+	MaxDim int
+}
+
+// ImageSizes are generated for every uploaded image, smallest first
+var ImageSizes = []ImageSize{
+	{Name: "thumb", MaxDim: 240},
+	{Name: "card", MaxDim: 720},
+	{Name: "full", MaxDim: 1600},
+}
+
+// ProcessedVariant is one encoded rendition ready for storage
+type ProcessedVariant struct {
+	Name        string
+	Format      string
+	Ext         string
+	ContentType string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+// ProcessedImage is an upload after orientation, resizing and re-encoding.
+// Re-encoding drops all EXIF/XMP metadata, including GPS position.
+type ProcessedImage struct {
+	Width    int
+	Height   int
+	BlurHash string
+	Variants []ProcessedVariant
+}
+
+// ProcessImage decodes an image of the sniffed contentType, applies its EXIF
+// orientation and renders every ImageSize as JPEG and WebP.
+func ProcessImage(data []byte, contentType string) (*ProcessedImage, error) {
+	decode, decodeConfig := image.Decode, image.DecodeConfig
+	if contentType == "image/heic" {
+		// heic only registers the "heic" brand, so call it directly for mif1 etc.
+		decode, decodeConfig = func(r io.Reader) (image.Image, string, error) {
+			m, err := heic.Decode(r)
+			return m, "heic", err
+		}, func(r io.Reader) (image.Config, string, error) {
+			c, err := heic.DecodeConfig(r)
+			return c, "heic", err
+		}
+	}
+
+	conf, _, err := decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("read image header: %w", err)
+	}
+	if conf.Width <= 0 || conf.Height <= 0 || conf.Width*conf.Height > maxImagePixels {
+		return nil, fmt.Errorf("image dimensions %dx%d not allowed", conf.Width, conf.Height)
+	}
+
+	src, _, err := decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+	// Scale down to the largest rendition before re-orienting; fitting is
+	// symmetric so the order doesn't change the result, only the cost.
+	largest := 0
+	for _, size := range ImageSizes {
+		largest = max(largest, size.MaxDim)
+	}
+	orientation := 1
+	if contentType == "image/jpeg" {
+		orientation = jpegOrientation(data)
+	}
+	base := applyOrientation(fitWithin(src, largest), orientation)
+
+	out := &ProcessedImage{Width: src.Bounds().Dx(), Height: src.Bounds().Dy()}
+	if orientation >= 5 {
+		out.Width, out.Height = out.Height, out.Width
+	}
+	for _, size := range ImageSizes {
+		resized := base
+		if size.MaxDim < largest {
+			resized = fitWithin(base, size.MaxDim)
+		}
+		w, h := resized.Bounds().Dx(), resized.Bounds().Dy()
+
+		var jpg bytes.Buffer
+		if err := jpeg.Encode(&jpg, resized, &jpeg.Options{Quality: 82}); err != nil {
+			return nil, fmt.Errorf("encode %s jpeg: %w", size.Name, err)
+		}
+		var wp bytes.Buffer
+		if err := webp.Encode(&wp, resized, webp.Options{Quality: 78, Method: 2}); err != nil {
+			return nil, fmt.Errorf("encode %s webp: %w", size.Name, err)
+		}
+
+		out.Variants = append(out.Variants,
+			ProcessedVariant{Name: size.Name, Format: "jpeg", Ext: ".jpg", ContentType: "image/jpeg", Width: w, Height: h, Data: jpg.Bytes()},
+			ProcessedVariant{Name: size.Name, Format: "webp", Ext: ".webp", ContentType: "image/webp", Width: w, Height: h, Data: wp.Bytes()},
+		)
+
+		// The smallest size is plenty for a 4x3 component placeholder
+		if out.BlurHash == "" {
+			if hash, err := blurhash.Encode(4, 3, resized); err == nil {
+				out.BlurHash = hash
+			}
+		}
+	}
+	return out, nil
+}
+
+// fitWithin scales img down so its longest side is at most maxDim. Images
+// already small enough are only copied, never upscaled.
+func fitWithin(img image.Image, maxDim int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > maxDim || h > maxDim {
+		if w >= h {
+			h = max(1, h*maxDim/w)
+			w = maxDim
+		} else {
+			w = max(1, w*maxDim/h)
+			h = maxDim
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// applyOrientation rotates/flips img so it displays upright for an EXIF
+// orientation value (1-8).
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	// orientations 5-8 swap width and height
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirror horizontal
+				dx, dy = w-1-x, y
+			case 3: // rotate 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirror vertical
+				dx, dy = x, h-1-y
+			case 5: // mirror horizontal + rotate 270 CW
+				dx, dy = y, x
+			case 6: // rotate 90 CW
+				dx, dy = h-1-y, x
+			case 7: // mirror horizontal + rotate 90 CW
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotate 270 CW
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the EXIF orientation tag (0x0112) from a JPEG's
+// APP1 segment. It returns 1 (upright) when absent or unreadable.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xD9 || marker == 0xDA { // end of image / start of scan
+			return 1
+		}
+		segLen := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		if segLen < 2 || pos+2+segLen > len(data) {
+			return 1
+		}
+		seg := data[pos+4 : pos+2+segLen]
+		if marker == 0xE1 && len(seg) > 14 && string(seg[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(seg[6:])
+		}
+		pos += 2 + segLen
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < entries; i++ {
+		e := ifd + 2 + i*12
+		if e+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[e:e+2]) == 0x0112 {
+			return int(order.Uint16(tiff[e+8 : e+10]))
+		}
+	}
+	return 1
+}
@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrateLegacyImages converts bare image URLs saved on hubs and events
+// before assets existed into asset records. Safe to run on every start.
+func MigrateLegacyImages(cfg *config.Config) {
+	for _, name := range []string{"hubs", "events"} {
+		n, err := migrateLegacyImages(cfg, name)
+		if err != nil {
+			log.Printf("⚠️ Could not migrate legacy images in %s: %v", name, err)
+			continue
+		}
+		if n > 0 {
+			log.Printf("✅ Migrated legacy images on %d %s", n, name)
+		}
+	}
+}
+
+func migrateLegacyImages(cfg *config.Config, collection string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	db := cfg.MongoClient.Database(cfg.DBName)
+	col := db.Collection(collection)
+	assetCol := db.Collection("assets")
+
+	cursor, err := col.Find(ctx, bson.M{"images": bson.M{"$elemMatch": bson.M{"$type": "string"}}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID     primitive.ObjectID `bson:"_id"`
+			UserID primitive.ObjectID `bson:"user_id"`
+			Images []models.Asset     `bson:"images"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return migrated, err
+		}
+
+		for i, img := range doc.Images {
+			if img.Provider != models.ProviderLegacy {
+				continue
+			}
+			img.ID = primitive.NewObjectID()
+			img.UploadedBy = doc.UserID
+			img.CreatedAt = doc.ID.Timestamp()
+			if publicID, err := extractPublicID(img.URL); err == nil && strings.Contains(img.URL, "res.cloudinary.com") {
+				img.Provider = models.ProviderCloudinary
+				img.Key = publicID
+			}
+			if _, err := assetCol.InsertOne(ctx, img); err != nil {
+				return migrated, fmt.Errorf("record asset: %w", err)
+			}
+			doc.Images[i] = img
+		}
+
+		if _, err := col.UpdateOne(ctx, bson.M{"_id": doc.ID}, bson.M{"$set": bson.M{"images": doc.Images}}); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, cursor.Err()
+}
+
+// BackfillHubPhotos gives every image on a hub an approved gallery record.
+// Hubs created before hub_photos existed only have Hub.Images. Safe to run
+// on every start: records are upserted by asset ID.
+func BackfillHubPhotos(cfg *config.Config) {
+	n, err := backfillHubPhotos(cfg)
+	if err != nil {
+		log.Printf("⚠️ Could not backfill hub photos: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("✅ Backfilled %d hub photos", n)
+	}
+}
+
+func backfillHubPhotos(cfg *config.Config) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	db := cfg.MongoClient.Database(cfg.DBName)
+	photoCol := db.Collection("hub_photos")
+
+	cursor, err := db.Collection("hubs").Find(ctx, bson.M{
+		"images.0":    bson.M{"$exists": true},
+		"merged_into": bson.M{"$exists": false},
+	}, options.Find().SetProjection(bson.M{"user_id": 1, "images": 1}))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	created := 0
+	for cursor.Next(ctx) {
+		var hub models.Hub
+		if err := cursor.Decode(&hub); err != nil {
+			return created, err
+		}
+
+		ids := make([]primitive.ObjectID, 0, len(hub.Images))
+		for _, img := range hub.Images {
+			if !img.ID.IsZero() {
+				ids = append(ids, img.ID)
+			}
+		}
+		known, err := photoCol.Distinct(ctx, "asset._id", bson.M{"asset._id": bson.M{"$in": ids}})
+		if err != nil {
+			return created, err
+		}
+		if len(known) == len(ids) {
+			continue
+		}
+		has := map[primitive.ObjectID]bool{}
+		for _, id := range known {
+			if oid, ok := id.(primitive.ObjectID); ok {
+				has[oid] = true
+			}
+		}
+
+		now := time.Now()
+		for i, img := range hub.Images {
+			if img.ID.IsZero() || has[img.ID] {
+				continue
+			}
+			uploader := img.UploadedBy
+			if uploader.IsZero() {
+				uploader = hub.UserID
+			}
+			p := models.HubPhoto{
+				ID:         primitive.NewObjectID(),
+				HubID:      hub.ID,
+				Asset:      img,
+				UploadedBy: uploader,
+				Status:     models.PhotoStatusApproved,
+				Position:   i,
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			}
+			res, err := photoCol.UpdateOne(ctx,
+				bson.M{"asset._id": img.ID},
+				bson.M{"$setOnInsert": p},
+				options.Update().SetUpsert(true))
+			if err != nil {
+				return created, err
+			}
+			if res.UpsertedCount > 0 {
+				created++
+			}
+		}
+	}
+	return created, cursor.Err()
+}
+
+// extractPublicID recovers a Cloudinary public ID from a legacy full URL,
+// e.g. https://res.cloudinary.com/demo/image/upload/v1234567890/events/abc123.jpg
+func extractPublicID(imageURL string) (string, error) {
+	parsedURL, err := url.Parse(imageURL)
+	if err != nil {
+		return "", err
+	}
+
+	_, rest, found := strings.Cut(parsedURL.Path, "/upload/")
+	if !found || rest == "" {
+		return "", fmt.Errorf("invalid cloudinary URL format")
+	}
+
+	// Drop the version segment (e.g. v1234567890)
+	parts := strings.Split(rest, "/")
+	if len(parts) > 1 && len(parts[0]) > 1 && parts[0][0] == 'v' && strings.Trim(parts[0][1:], "0123456789") == "" {
+		parts = parts[1:]
+	}
+
+	joined := path.Join(parts...)
+	return strings.TrimSuffix(joined, path.Ext(joined)), nil
+}
@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const week = 7 * 24 * time.Hour
+
+// HubLocation resolves a hub's time zone, falling back to the configured default
+func HubLocation(cfg *config.Config, tz string) *time.Location {
+	if tz != "" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			return loc
+		}
+	}
+	loc, err := time.LoadLocation(cfg.Popular.DefaultTimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// AddOccupancy adds one person's presence from start to end to hist,
+// splitting it over local weekday/hour slots by the fraction of each hour
+// they were there.
+func AddOccupancy(hist *[7][24]float64, start, end time.Time, loc *time.Location) {
+	t := start.In(loc)
+	end = end.In(loc)
+	for t.Before(end) {
+		next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		if !next.After(t) { // DST edge: make sure we always move forward
+			next = t.Add(time.Hour)
+		}
+		if next.After(end) {
+			next = end
+		}
+		hist[t.Weekday()][t.Hour()] += next.Sub(t).Hours()
+		t = next
+	}
+}
+
+// BuildPopularTimes turns a hub's check-ins into a histogram. Each slot is
+// averaged over the weeks of history seen, capped at lookback.
+func BuildPopularTimes(hubID primitive.ObjectID, checkIns []models.CheckIn, loc *time.Location, now time.Time, lookback time.Duration) models.PopularTimes {
+	pt := models.PopularTimes{HubID: hubID, TimeZone: loc.String(), UpdatedAt: now}
+	if len(checkIns) == 0 {
+		return pt
+	}
+
+	from := now.Add(-lookback)
+	earliest := now
+	for _, ci := range checkIns {
+		start := ci.CreatedAt
+		end := ci.ExpiresAt
+		if ci.CheckedOutAt != nil {
+			end = *ci.CheckedOutAt
+		}
+		if end.After(now) {
+			end = now
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if !end.After(start) {
+			continue
+		}
+		if start.Before(earliest) {
+			earliest = start
+		}
+		AddOccupancy(&pt.Occupancy, start, end, loc)
+		pt.CheckIns++
+	}
+
+	pt.Weeks = math.Max(1, now.Sub(earliest).Hours()/week.Hours())
+	peak := 0.0
+	for d := range pt.Occupancy {
+		for h := range pt.Occupancy[d] {
+			pt.Occupancy[d][h] = math.Round(pt.Occupancy[d][h]/pt.Weeks*100) / 100
+			peak = math.Max(peak, pt.Occupancy[d][h])
+		}
+	}
+	if peak > 0 {
+		for d := range pt.Occupancy {
+			for h := range pt.Occupancy[d] {
+				pt.Relative[d][h] = int(math.Round(pt.Occupancy[d][h] / peak * 100))
+			}
+		}
+	}
+	return pt
+}
+
+// PopularTimesNow reads the slot for the current local hour
+func PopularTimesNow(pt models.PopularTimes, loc *time.Location, now time.Time, quietThreshold int) models.PopularNow {
+	local := now.In(loc)
+	d, h := int(local.Weekday()), local.Hour()
+	return models.PopularNow{
+		Weekday:   d,
+		Hour:      h,
+		Occupancy: pt.Occupancy[d][h],
+		Relative:  pt.Relative[d][h],
+		Quiet:     pt.Relative[d][h] <= quietThreshold,
+	}
+}
+
+// RefreshPopularTimes rebuilds the histogram of every hub with check-ins in
+// the lookback window and returns how many were updated. Histograms of hubs
+// whose check-ins have all aged out are removed.
+func RefreshPopularTimes(ctx context.Context, cfg *config.Config) (int, error) {
+	db := cfg.MongoClient.Database(cfg.DBName)
+	now := time.Now()
+	from := now.Add(-cfg.Popular.Lookback)
+
+	// check-ins that were still running at the start of the window count too
+	window := bson.M{"$or": bson.A{
+		bson.M{"created_at": bson.M{"$gte": from}},
+		bson.M{"expires_at": bson.M{"$gte": from}},
+	}}
+	hubIDs, err := db.Collection("checkins").Distinct(ctx, "hub_id", window)
+	if err != nil {
+		return 0, err
+	}
+
+	refreshed := make([]primitive.ObjectID, 0, len(hubIDs))
+	for _, raw := range hubIDs {
+		hubID, ok := raw.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+
+		var hub models.Hub
+		if err := db.Collection("hubs").FindOne(ctx, bson.M{"_id": hubID}).Decode(&hub); err != nil {
+			continue // deleted hub
+		}
+
+		filter := bson.M{"hub_id": hubID}
+		for k, v := range window {
+			filter[k] = v
+		}
+		cursor, err := db.Collection("checkins").Find(ctx, filter)
+		if err != nil {
+			return len(refreshed), err
+		}
+		var checkIns []models.CheckIn
+		if err := cursor.All(ctx, &checkIns); err != nil {
+			return len(refreshed), err
+		}
+
+		pt := BuildPopularTimes(hubID, checkIns, HubLocation(cfg, hub.TimeZone), now, cfg.Popular.Lookback)
+		_, err = db.Collection("popular_times").UpdateOne(ctx,
+			bson.M{"hub_id": hubID},
+			bson.M{"$set": bson.M{
+				"timezone":   pt.TimeZone,
+				"occupancy":  pt.Occupancy,
+				"relative":   pt.Relative,
+				"checkins":   pt.CheckIns,
+				"weeks":      pt.Weeks,
+				"updated_at": pt.UpdatedAt,
+			}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return len(refreshed), err
+		}
+		refreshed = append(refreshed, hubID)
+	}
+
+	if _, err := db.Collection("popular_times").DeleteMany(ctx, bson.M{"hub_id": bson.M{"$nin": refreshed}}); err != nil {
+		return len(refreshed), err
+	}
+	return len(refreshed), nil
+}
+
+// StartPopularTimesJob refreshes histograms at startup and then every
+// cfg.Popular.Interval
+func StartPopularTimesJob(cfg *config.Config) {
+	if cfg.Popular.Interval <= 0 {
+		log.Println("📊 Popular times job disabled")
+		return
+	}
+
+	run := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		if n, err := RefreshPopularTimes(ctx, cfg); err != nil {
+			log.Printf("⚠️ Popular times refresh failed: %v", err)
+		} else {
+			log.Printf("📊 Popular times refreshed for %d hub(s)", n)
+		}
+	}
+
+	go func() {
+		run()
+		ticker := time.NewTicker(cfg.Popular.Interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			run()
+		}
+	}()
+	log.Printf("📊 Popular times every %s over the last %s", cfg.Popular.Interval, cfg.Popular.Lookback)
+}
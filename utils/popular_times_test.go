@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	models "github.com/phillip/contribution-tracker-go/models"
+)
+
+// 2 March 2026 is a Monday
+func at(day, hour, min int) time.Time {
+	return time.Date(2026, 3, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestAddOccupancy(t *testing.T) {
+	eat := time.FixedZone("EAT", 3*60*60)
+	type slot struct{ day, hour int }
+	tests := []struct {
+		name       string
+		start, end time.Time
+		loc        *time.Location
+		want       map[slot]float64
+	}{
+		{"within one hour", at(2, 10, 15), at(2, 10, 45), time.UTC, map[slot]float64{{1, 10}: 0.5}},
+		{
+			"split across hours", at(2, 10, 30), at(2, 12, 15), time.UTC,
+			map[slot]float64{{1, 10}: 0.5, {1, 11}: 1, {1, 12}: 0.25},
+		},
+		{
+			"across midnight", at(1, 23, 30), at(2, 0, 30), time.UTC,
+			map[slot]float64{{0, 23}: 0.5, {1, 0}: 0.5},
+		},
+		{"in local time", at(2, 7, 0), at(2, 8, 0), eat, map[slot]float64{{1, 10}: 1}},
+		{"empty stay", at(2, 10, 0), at(2, 10, 0), time.UTC, map[slot]float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hist [7][24]float64
+			AddOccupancy(&hist, tt.start, tt.end, tt.loc)
+			for d := range hist {
+				for h := range hist[d] {
+					if want := tt.want[slot{d, h}]; hist[d][h] != want {
+						t.Errorf("hist[%d][%d] = %v, want %v", d, h, hist[d][h], want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBuildPopularTimes(t *testing.T) {
+	now := at(2, 12, 0)
+	checkIn := func(start, expires time.Time, out *time.Time) models.CheckIn {
+		return models.CheckIn{CreatedAt: start, ExpiresAt: expires, CheckedOutAt: out}
+	}
+	out := at(2, 11, 30)
+
+	t.Run("no check-ins", func(t *testing.T) {
+		pt := BuildPopularTimes(testID(1), nil, time.UTC, now, 2*week)
+		if pt.HubID != testID(1) || pt.CheckIns != 0 || pt.Weeks != 0 || pt.TimeZone != "UTC" {
+			t.Errorf("BuildPopularTimes() = %+v, want an empty histogram", pt)
+		}
+	})
+
+	t.Run("averaged over the lookback", func(t *testing.T) {
+		checkIns := []models.CheckIn{
+			checkIn(at(2, 10, 0).Add(-2*week).Add(time.Hour), at(2, 13, 0).Add(-2*week), nil), // clipped to the window
+			checkIn(at(2, 10, 0).Add(-3*week), at(2, 11, 0).Add(-3*week), nil),                // before the window
+			checkIn(at(2, 10, 0).Add(-week), at(2, 11, 0).Add(-week), nil),
+			checkIn(at(2, 10, 0), at(2, 11, 0), nil),
+			checkIn(at(2, 11, 0), at(2, 13, 0), &out), // checked out early
+			checkIn(at(2, 11, 30), at(2, 14, 0), nil), // still here
+		}
+		pt := BuildPopularTimes(testID(1), checkIns, time.UTC, now, 2*week)
+		if pt.CheckIns != 5 || pt.Weeks != 2 {
+			t.Fatalf("CheckIns, Weeks = %d, %v; want 5, 2", pt.CheckIns, pt.Weeks)
+		}
+		tests := []struct {
+			hour      int
+			occupancy float64
+			relative  int
+		}{
+			{9, 0, 0},
+			{10, 1, 100},
+			{11, 0.5, 50},
+			{12, 0.5, 50},
+			{13, 0, 0},
+		}
+		for _, tt := range tests {
+			if got := pt.Occupancy[1][tt.hour]; got != tt.occupancy {
+				t.Errorf("Occupancy[Mon][%d] = %v, want %v", tt.hour, got, tt.occupancy)
+			}
+			if got := pt.Relative[1][tt.hour]; got != tt.relative {
+				t.Errorf("Relative[Mon][%d] = %v, want %v", tt.hour, got, tt.relative)
+			}
+		}
+	})
+
+	t.Run("short history counts as a week", func(t *testing.T) {
+		checkIns := []models.CheckIn{checkIn(at(1, 10, 0), at(1, 11, 0), nil)}
+		pt := BuildPopularTimes(testID(1), checkIns, time.UTC, now, 4*week)
+		if pt.Weeks != 1 || pt.Occupancy[0][10] != 1 || pt.Relative[0][10] != 100 {
+			t.Errorf("Weeks, Occupancy, Relative = %v, %v, %v; want 1, 1, 100",
+				pt.Weeks, pt.Occupancy[0][10], pt.Relative[0][10])
+		}
+	})
+}
+
+func TestPopularTimesNow(t *testing.T) {
+	var pt models.PopularTimes
+	pt.Occupancy[1][15], pt.Relative[1][15] = 2.5, 80
+	pt.Relative[1][16] = 20
+
+	eat := time.FixedZone("EAT", 3*60*60)
+	tests := []struct {
+		name string
+		now  time.Time
+		want models.PopularNow
+	}{
+		{"busy hour", at(2, 12, 10), models.PopularNow{Weekday: 1, Hour: 15, Occupancy: 2.5, Relative: 80}},
+		{"quiet hour", at(2, 13, 59), models.PopularNow{Weekday: 1, Hour: 16, Relative: 20, Quiet: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PopularTimesNow(pt, eat, tt.now, 25); got != tt.want {
+				t.Errorf("PopularTimesNow() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
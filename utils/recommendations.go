@@ -0,0 +1,255 @@
+package utils
+
+import (
+	"math"
+	"sort"
+
+	models "github.com/phillip/contribution-tracker-go/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Amenity preferences accepted by recommendations
+const (
+	PreferFastWiFi = "fast_wifi"
+	PreferQuiet    = "quiet"
+)
+
+// Weights of the recommendation score components. Each component is
+// scaled to 0..1 before weighting.
+const (
+	recWeightQuality    = 1.0
+	recWeightProximity  = 2.0
+	recWeightSimilarity = 1.5
+	recWeightAmenity    = 1.0
+	recWeightPopularity = 0.5
+	recWeightAffinity   = 0.5
+
+	// ratings are shrunk towards this prior by recPriorReviews reviews
+	recPriorRating  = 3.5
+	recPriorReviews = 5.0
+
+	// distance at which proximity and location similarity fall to 1/e
+	recProximityScaleMeters = 2000.0
+	recSimilarScaleMeters   = 3000.0
+
+	// download speed that counts as fully "fast"
+	recFastWiFiMbps = 50.0
+)
+
+// RecommendationProfile is what we know about the caller's taste
+type RecommendationProfile struct {
+	Origin      *models.Coordinates            // where they are, if supplied
+	Preferences map[string]bool                // explicit amenity preferences
+	Affinity    map[primitive.ObjectID]float64 // engagement per hub from favorites, reviews and check-ins
+	Disliked    map[primitive.ObjectID]bool    // hubs they rated poorly
+	Liked       []models.Hub                   // hubs they favorited or rated highly
+}
+
+// ColdStart reports whether the profile has no history to personalize from
+func (p RecommendationProfile) ColdStart() bool {
+	return len(p.Liked) == 0 && len(p.Affinity) == 0
+}
+
+// RecommendationCandidate is a hub with the aggregates scoring needs
+type RecommendationCandidate struct {
+	Hub       models.Hub
+	AvgRating float64 // of published reviews
+	Reviews   int
+	QuietNow  bool // usually quiet at this hour
+}
+
+// Recommendation is a scored hub
+type Recommendation struct {
+	Hub     models.Hub `json:"hub"`
+	Score   float64    `json:"score"`
+	Reasons []string   `json:"reasons"`
+}
+
+// ProfileAffinity is the engagement weight of one hub: a favorite counts
+// 1, a review its rating relative to neutral, each check-in 0.25 (up to 1).
+func ProfileAffinity(favorite bool, rating int, checkIns int) float64 {
+	a := 0.0
+	if favorite {
+		a++
+	}
+	if rating > 0 {
+		a += float64(rating-3) / 2
+	}
+	a += math.Min(float64(checkIns)*0.25, 1)
+	return a
+}
+
+// ScoreHub scores one candidate for the profile. It is a pure function:
+// the same inputs always give the same score and reasons.
+func ScoreHub(p RecommendationProfile, c RecommendationCandidate) Recommendation {
+	rec := Recommendation{Hub: c.Hub, Reasons: []string{}}
+	hasCoords := c.Hub.Coordinates.Lat != 0 || c.Hub.Coordinates.Lng != 0
+
+	// --- Quality: Bayesian average rating ---
+	quality := clamp01((BayesianRating(c.AvgRating, c.Reviews) - 1) / 4)
+	qualityWeight := recWeightQuality
+	if p.ColdStart() {
+		qualityWeight *= 2 // fall back to top-rated nearby
+	}
+	rec.Score += qualityWeight * quality
+	if c.Reviews >= 3 && c.AvgRating >= 4 {
+		rec.Reasons = append(rec.Reasons, "highly rated")
+	}
+
+	// --- Proximity to the supplied location ---
+	if p.Origin != nil && hasCoords {
+		d := HaversineMeters(*p.Origin, c.Hub.Coordinates)
+		proximity := math.Exp(-d / recProximityScaleMeters)
+		rec.Score += recWeightProximity * proximity
+		if d <= 1000 {
+			rec.Reasons = append(rec.Reasons, "nearby")
+		}
+	}
+
+	// --- Similarity to hubs they liked ---
+	best := 0.0
+	for _, liked := range p.Liked {
+		if liked.ID == c.Hub.ID {
+			continue
+		}
+		if s := hubSimilarity(liked, c.Hub); s > best {
+			best = s
+		}
+	}
+	rec.Score += recWeightSimilarity * best
+	if best >= 0.5 {
+		rec.Reasons = append(rec.Reasons, "similar to hubs you like")
+	}
+
+	// --- Amenity preferences ---
+	if p.Preferences[PreferFastWiFi] && c.Hub.WiFi != nil {
+		fast := clamp01(c.Hub.WiFi.DownloadMbps / recFastWiFiMbps)
+		rec.Score += recWeightAmenity * fast
+		if fast >= 1 {
+			rec.Reasons = append(rec.Reasons, "fast wifi")
+		}
+	}
+	if p.Preferences[PreferQuiet] && c.QuietNow {
+		rec.Score += recWeightAmenity
+		rec.Reasons = append(rec.Reasons, "usually quiet now")
+	}
+
+	// --- Popularity: favorites, with diminishing returns ---
+	rec.Score += recWeightPopularity * clamp01(math.Log1p(float64(c.Hub.FavoritesCount))/math.Log(100))
+
+	// --- Their own engagement with the hub ---
+	if a := p.Affinity[c.Hub.ID]; a != 0 {
+		rec.Score += recWeightAffinity * math.Max(-1, math.Min(a, 2)) / 2
+		if a > 0 {
+			rec.Reasons = append(rec.Reasons, "you've been here")
+		}
+	}
+
+	rec.Score = math.Round(rec.Score*1000) / 1000
+	return rec
+}
+
+// RankHubs scores candidates and returns the best limit of them. Hubs the
+// caller disliked are dropped. Ties are broken by hub ID so the order is
+// stable.
+func RankHubs(p RecommendationProfile, candidates []RecommendationCandidate, limit int) []Recommendation {
+	recs := make([]Recommendation, 0, len(candidates))
+	for _, c := range candidates {
+		if p.Disliked[c.Hub.ID] {
+			continue
+		}
+		recs = append(recs, ScoreHub(p, c))
+	}
+	sort.SliceStable(recs, func(i, j int) bool {
+		if recs[i].Score != recs[j].Score {
+			return recs[i].Score > recs[j].Score
+		}
+		return recs[i].Hub.ID.Hex() < recs[j].Hub.ID.Hex()
+	})
+	if limit > 0 && len(recs) > limit {
+		recs = recs[:limit]
+	}
+	return recs
+}
+
+// hubSimilarity compares two hubs (0..1) by what kind of place they are
+// (categories, tags, amenities, price band), where they are, their name
+// and their Wi-Fi. Attributes missing on either hub count as no match.
+func hubSimilarity(a, b models.Hub) float64 {
+	s := 0.2*setSimilarity(a.Categories, b.Categories) +
+		0.15*setSimilarity(a.Tags, b.Tags) +
+		0.1*setSimilarity(a.Amenities, b.Amenities) +
+		0.1*priceBandSimilarity(a.PriceBand, b.PriceBand) +
+		0.2*TitleSimilarity(a.Title, b.Title)
+
+	aCoords := a.Coordinates.Lat != 0 || a.Coordinates.Lng != 0
+	bCoords := b.Coordinates.Lat != 0 || b.Coordinates.Lng != 0
+	if aCoords && bCoords {
+		s += 0.2 * math.Exp(-HaversineMeters(a.Coordinates, b.Coordinates)/recSimilarScaleMeters)
+	}
+
+	if a.WiFi != nil && b.WiFi != nil {
+		hi := math.Max(a.WiFi.DownloadMbps, b.WiFi.DownloadMbps)
+		if hi > 0 {
+			s += 0.05 * math.Min(a.WiFi.DownloadMbps, b.WiFi.DownloadMbps) / hi
+		}
+	}
+	return s
+}
+
+// setSimilarity is the Jaccard index of two lists (0 if either is empty)
+func setSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	set := make(map[string]bool, len(a))
+	for _, v := range a {
+		set[v] = true
+	}
+	union := len(set)
+	shared := 0
+	seen := map[string]bool{}
+	for _, v := range b {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		if set[v] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	return float64(shared) / float64(union)
+}
+
+// priceBands orders the price bands from free to expensive
+var priceBands = []string{models.PriceBandFree, models.PriceBandLow, models.PriceBandMedium, models.PriceBandHigh}
+
+// priceBandSimilarity is 1 for the same band, 0.5 for neighbouring bands
+// and 0 otherwise or when either is unknown
+func priceBandSimilarity(a, b string) float64 {
+	ia, ib := -1, -1
+	for i, p := range priceBands {
+		if p == a {
+			ia = i
+		}
+		if p == b {
+			ib = i
+		}
+	}
+	if ia < 0 || ib < 0 {
+		return 0
+	}
+	switch d := ia - ib; {
+	case d == 0:
+		return 1
+	case d == 1 || d == -1:
+		return 0.5
+	}
+	return 0
+}
+
+func clamp01(x float64) float64 {
+	return math.Max(0, math.Min(1, x))
+}
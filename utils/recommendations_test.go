@@ -0,0 +1,262 @@
+package utils
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	models "github.com/phillip/contribution-tracker-go/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// testID returns a fixed ObjectID ending in n, so IDs sort by n
+func testID(n byte) primitive.ObjectID {
+	var id primitive.ObjectID
+	id[len(id)-1] = n
+	return id
+}
+
+func candidate(n byte, avg float64, reviews int) RecommendationCandidate {
+	return RecommendationCandidate{
+		Hub:       models.Hub{ID: testID(n), Title: "Hub"},
+		AvgRating: avg,
+		Reviews:   reviews,
+	}
+}
+
+func hasReason(rec Recommendation, reason string) bool {
+	for _, r := range rec.Reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+func TestProfileAffinity(t *testing.T) {
+	tests := []struct {
+		name     string
+		favorite bool
+		rating   int
+		checkIns int
+		want     float64
+	}{
+		{"nothing", false, 0, 0, 0},
+		{"favorite", true, 0, 0, 1},
+		{"five stars", false, 5, 0, 1},
+		{"one star", false, 1, 0, -1},
+		{"neutral review", false, 3, 0, 0},
+		{"check-ins capped", false, 0, 10, 1},
+		{"everything", true, 5, 2, 2.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProfileAffinity(tt.favorite, tt.rating, tt.checkIns); got != tt.want {
+				t.Errorf("ProfileAffinity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreHubColdStart(t *testing.T) {
+	cold := RecommendationProfile{}
+	warm := RecommendationProfile{Affinity: map[primitive.ObjectID]float64{testID(99): 1}}
+	if !cold.ColdStart() || warm.ColdStart() {
+		t.Fatalf("ColdStart() = %v/%v, want true/false", cold.ColdStart(), warm.ColdStart())
+	}
+
+	// 5.0 over 10 reviews shrinks to 4.5, i.e. quality 0.875
+	c := candidate(1, 5, 10)
+	tests := []struct {
+		name    string
+		profile RecommendationProfile
+		want    float64
+	}{
+		{"cold start doubles quality", cold, 1.75},
+		{"with history", warm, 0.875},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := ScoreHub(tt.profile, c)
+			if rec.Score != tt.want {
+				t.Errorf("Score = %v, want %v", rec.Score, tt.want)
+			}
+			if !hasReason(rec, "highly rated") {
+				t.Errorf("Reasons = %v, want %q", rec.Reasons, "highly rated")
+			}
+		})
+	}
+}
+
+func TestScoreHubAffinity(t *testing.T) {
+	// no reviews: quality is the prior, 0.625
+	c := candidate(1, 0, 0)
+	tests := []struct {
+		name     string
+		affinity float64
+		want     float64
+		reason   bool
+	}{
+		{"none", 0, 0.625, false},
+		{"engaged", 1, 0.875, true},
+		{"capped", 5, 1.125, true},
+		{"disliked", -2, 0.375, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := RecommendationProfile{Affinity: map[primitive.ObjectID]float64{
+				testID(99): 1, // keeps the profile out of cold start
+				c.Hub.ID:   tt.affinity,
+			}}
+			rec := ScoreHub(p, c)
+			if rec.Score != tt.want {
+				t.Errorf("Score = %v, want %v", rec.Score, tt.want)
+			}
+			if got := hasReason(rec, "you've been here"); got != tt.reason {
+				t.Errorf("Reasons = %v, want \"you've been here\" %v", rec.Reasons, tt.reason)
+			}
+		})
+	}
+}
+
+func TestScoreHubDeterministic(t *testing.T) {
+	origin := models.Coordinates{Lat: -1.2625, Lng: 36.8065}
+	p := RecommendationProfile{
+		Origin:      &origin,
+		Preferences: map[string]bool{PreferFastWiFi: true},
+		Liked:       []models.Hub{{ID: testID(9), Title: "Java House Westlands", Coordinates: origin}},
+	}
+	c := candidate(1, 4.5, 12)
+	c.Hub.Title = "Java House Kilimani"
+	c.Hub.Coordinates = models.Coordinates{Lat: -1.29, Lng: 36.785}
+	c.Hub.WiFi = &models.WiFiStats{DownloadMbps: 80}
+
+	first := ScoreHub(p, c)
+	for i := 0; i < 10; i++ {
+		if again := ScoreHub(p, c); !reflect.DeepEqual(first, again) {
+			t.Fatalf("ScoreHub() = %+v, then %+v", first, again)
+		}
+	}
+	if !hasReason(first, "fast wifi") {
+		t.Errorf("Reasons = %v, want %q", first.Reasons, "fast wifi")
+	}
+}
+
+func TestRankHubs(t *testing.T) {
+	origin := models.Coordinates{Lat: -1.2625, Lng: 36.8065}
+	near := candidate(5, 4, 10)
+	near.Hub.Coordinates = origin
+	far := candidate(6, 4, 10)
+	far.Hub.Coordinates = models.Coordinates{Lat: -4.025, Lng: 39.715}
+
+	tests := []struct {
+		name       string
+		profile    RecommendationProfile
+		candidates []RecommendationCandidate
+		limit      int
+		want       []primitive.ObjectID
+	}{
+		{
+			name:       "ties broken by id",
+			candidates: []RecommendationCandidate{candidate(3, 4, 5), candidate(1, 4, 5), candidate(2, 4, 5)},
+			want:       []primitive.ObjectID{testID(1), testID(2), testID(3)},
+		},
+		{
+			name:       "limit keeps the best",
+			candidates: []RecommendationCandidate{candidate(3, 4, 5), candidate(1, 4, 5), candidate(2, 4, 5)},
+			limit:      2,
+			want:       []primitive.ObjectID{testID(1), testID(2)},
+		},
+		{
+			name:       "better rated first",
+			candidates: []RecommendationCandidate{candidate(1, 3, 20), candidate(2, 5, 20)},
+			want:       []primitive.ObjectID{testID(2), testID(1)},
+		},
+		{
+			name:       "disliked hubs dropped",
+			profile:    RecommendationProfile{Disliked: map[primitive.ObjectID]bool{testID(2): true}},
+			candidates: []RecommendationCandidate{candidate(1, 3, 20), candidate(2, 5, 20)},
+			want:       []primitive.ObjectID{testID(1)},
+		},
+		{
+			name:       "nearby first",
+			profile:    RecommendationProfile{Origin: &origin},
+			candidates: []RecommendationCandidate{far, near},
+			want:       []primitive.ObjectID{testID(5), testID(6)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recs := RankHubs(tt.profile, tt.candidates, tt.limit)
+			got := make([]primitive.ObjectID, len(recs))
+			for i, r := range recs {
+				got[i] = r.Hub.ID
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RankHubs() order = %v, want %v", got, tt.want)
+			}
+			for i := 1; i < len(recs); i++ {
+				if recs[i].Score > recs[i-1].Score || math.IsNaN(recs[i].Score) {
+					t.Errorf("scores not descending: %v then %v", recs[i-1].Score, recs[i].Score)
+				}
+			}
+		})
+	}
+}
+
+func TestSetSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want float64
+	}{
+		{"empty", nil, []string{"cafe"}, 0},
+		{"same", []string{"cafe", "library"}, []string{"library", "cafe"}, 1},
+		{"half", []string{"cafe", "library"}, []string{"cafe", "cowork"}, 1.0 / 3},
+		{"repeats ignored", []string{"cafe"}, []string{"cafe", "cafe"}, 1},
+		{"disjoint", []string{"cafe"}, []string{"hotel"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setSimilarity(tt.a, tt.b); got != tt.want {
+				t.Errorf("setSimilarity(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHubSimilarity(t *testing.T) {
+	liked := models.Hub{
+		Title:      "Java House",
+		Categories: []string{"cafe"},
+		Tags:       []string{"quiet", "outlets"},
+		Amenities:  []string{"power_outlets", "wifi"},
+		PriceBand:  models.PriceBandMedium,
+	}
+	tests := []struct {
+		name string
+		hub  models.Hub
+		want float64
+	}{
+		// titles still share a few letters: 0.2 × 0.25
+		{"different kind of place", models.Hub{Title: "Sarit Centre", Categories: []string{"mall"}, PriceBand: models.PriceBandFree}, 0.05},
+		{
+			"same kind of place, other name",
+			models.Hub{Title: "Artcaffe", Categories: []string{"cafe"}, Tags: []string{"quiet", "outlets"}, Amenities: []string{"wifi", "power_outlets"}, PriceBand: models.PriceBandMedium},
+			0.59, // 0.55 from kind of place, 0.04 from the title
+		},
+		{
+			"neighbouring price band",
+			models.Hub{Title: "Artcaffe", Categories: []string{"cafe"}, PriceBand: models.PriceBandHigh},
+			0.29,
+		},
+		{"same name only", models.Hub{Title: "Java House"}, 0.2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := math.Round(hubSimilarity(liked, tt.hub)*1000) / 1000; got != tt.want {
+				t.Errorf("hubSimilarity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
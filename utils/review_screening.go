@@ -0,0 +1,301 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//go:embed data/blocked_words.txt
+var defaultBlockedWords string
+
+// ScreenInput is a review about to be stored
+type ScreenInput struct {
+	ReviewID primitive.ObjectID // zero when the review is new
+	UserID   primitive.ObjectID
+	HubID    primitive.ObjectID
+	Text     string
+}
+
+// ReviewScreener is one check in the screening pipeline. It returns the
+// reasons to hold the review, or none if it looks fine.
+type ReviewScreener interface {
+	Name() string
+	Screen(ctx context.Context, in ScreenInput) ([]models.ScreeningFlag, error)
+}
+
+var reviewScreeners []ReviewScreener
+
+// InitScreening builds the default pipeline: word list, link/phone spam,
+// copied text and posting velocity.
+func InitScreening(cfg *config.Config) error {
+	words, err := loadBlockedWords(cfg.Screening)
+	if err != nil {
+		return err
+	}
+
+	reviewScreeners = []ReviewScreener{
+		NewWordListScreener(words),
+		NewSpamScreener(cfg.Screening.MaxLinks),
+		NewDuplicateScreener(cfg, cfg.Screening.DuplicateMinLen),
+		NewVelocityScreener(cfg, cfg.Screening.VelocityWindow, cfg.Screening.VelocityMax),
+	}
+	log.Printf("🛡️ Review screening: %d checks, %d blocked words", len(reviewScreeners), len(words))
+	return nil
+}
+
+// RegisterReviewScreener appends a custom check to the pipeline
+func RegisterReviewScreener(s ReviewScreener) {
+	reviewScreeners = append(reviewScreeners, s)
+}
+
+// ScreenReview runs every check and collects their flags. Rating-only
+// reviews go through the pipeline too: text checks pass blank text, but
+// velocity and custom checks still apply. A check that errors is logged
+// and skipped so a database hiccup never blocks posting.
+func ScreenReview(ctx context.Context, in ScreenInput) []models.ScreeningFlag {
+	flags := []models.ScreeningFlag{}
+	for _, s := range reviewScreeners {
+		f, err := s.Screen(ctx, in)
+		if err != nil {
+			log.Printf("⚠️ review screener %s failed: %v", s.Name(), err)
+			continue
+		}
+		flags = append(flags, f...)
+	}
+	return flags
+}
+
+// ReviewTextHash fingerprints a comment so copies with different case,
+// spacing or punctuation still match. Empty for blank text.
+func ReviewTextHash(text string) string {
+	norm := strings.Join(screeningWords(text), " ")
+	if norm == "" {
+		return ""
+	}
+	sum := sha1.Sum([]byte(norm))
+	return hex.EncodeToString(sum[:])
+}
+
+// =============================
+// Word list
+// =============================
+
+// WordListScreener holds reviews containing blocked words or phrases
+type WordListScreener struct {
+	words   map[string]bool
+	phrases []string
+}
+
+func NewWordListScreener(list []string) *WordListScreener {
+	s := &WordListScreener{words: map[string]bool{}}
+	for _, w := range list {
+		norm := strings.Join(screeningWords(w), " ")
+		switch {
+		case norm == "":
+		case strings.Contains(norm, " "):
+			s.phrases = append(s.phrases, " "+norm+" ")
+		default:
+			s.words[norm] = true
+		}
+	}
+	return s
+}
+
+func (s *WordListScreener) Name() string { return "word_list" }
+
+func (s *WordListScreener) Screen(_ context.Context, in ScreenInput) ([]models.ScreeningFlag, error) {
+	words := screeningWords(in.Text)
+	for _, w := range words {
+		if s.words[w] {
+			return []models.ScreeningFlag{{Check: s.Name(), Reason: models.ReportReasonOffensive, Detail: "blocked word"}}, nil
+		}
+	}
+	joined := " " + strings.Join(words, " ") + " "
+	for _, p := range s.phrases {
+		if strings.Contains(joined, p) {
+			return []models.ScreeningFlag{{Check: s.Name(), Reason: models.ReportReasonOffensive, Detail: "blocked phrase"}}, nil
+		}
+	}
+	return nil, nil
+}
+
+// loadBlockedWords merges the bundled list with the configured extras
+func loadBlockedWords(sc config.ScreeningConfig) ([]string, error) {
+	words := parseWordList(defaultBlockedWords)
+	if sc.WordListFile != "" {
+		data, err := os.ReadFile(sc.WordListFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading word list: %w", err)
+		}
+		words = append(words, parseWordList(string(data))...)
+	}
+	return append(words, sc.BlockedWords...), nil
+}
+
+func parseWordList(data string) []string {
+	var words []string
+	sc := bufio.NewScanner(strings.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	return words
+}
+
+// leetReplacer undoes common character swaps used to dodge word lists
+var leetReplacer = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s", "!", "i")
+
+// screeningWords lowercases text, undoes leetspeak inside words and splits
+// it into words
+func screeningWords(text string) []string {
+	fields := strings.Fields(strings.ToLower(text))
+	words := make([]string, 0, len(fields))
+	for _, f := range fields {
+		// only rewrite digits/symbols in tokens that also contain letters,
+		// so prices and times stay as they are
+		if strings.IndexFunc(f, unicode.IsLetter) >= 0 {
+			f = strings.TrimLeft(strings.TrimRight(f, ".,!?;:)]}\"'"), "([{\"'")
+			f = leetReplacer.Replace(f)
+		}
+		for _, w := range strings.FieldsFunc(f, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// =============================
+// Links and phone numbers
+// =============================
+
+var (
+	linkPattern  = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+|\b[a-z0-9-]+\.(?:com|net|org|info|biz|xyz|io|me|co|ke|co\.ke|ly|link|top)\b`)
+	phonePattern = regexp.MustCompile(`\+?\d[\d\s().-]{7,}\d`)
+)
+
+// SpamScreener holds reviews that advertise links or phone numbers
+type SpamScreener struct {
+	maxLinks int
+}
+
+func NewSpamScreener(maxLinks int) *SpamScreener {
+	return &SpamScreener{maxLinks: maxLinks}
+}
+
+func (s *SpamScreener) Name() string { return "spam" }
+
+func (s *SpamScreener) Screen(_ context.Context, in ScreenInput) ([]models.ScreeningFlag, error) {
+	var flags []models.ScreeningFlag
+	if n := len(linkPattern.FindAllString(in.Text, -1)); n > s.maxLinks {
+		flags = append(flags, models.ScreeningFlag{Check: s.Name(), Reason: models.ReportReasonSpam, Detail: fmt.Sprintf("%d link(s)", n)})
+	}
+	for _, m := range phonePattern.FindAllString(in.Text, -1) {
+		digits := 0
+		for _, r := range m {
+			if unicode.IsDigit(r) {
+				digits++
+			}
+		}
+		if digits >= 9 {
+			flags = append(flags, models.ScreeningFlag{Check: s.Name(), Reason: models.ReportReasonSpam, Detail: "phone number"})
+			break
+		}
+	}
+	return flags, nil
+}
+
+// =============================
+// Copied text
+// =============================
+
+// DuplicateScreener holds reviews whose text was already posted on another hub
+type DuplicateScreener struct {
+	cfg    *config.Config
+	minLen int
+}
+
+func NewDuplicateScreener(cfg *config.Config, minLen int) *DuplicateScreener {
+	return &DuplicateScreener{cfg: cfg, minLen: minLen}
+}
+
+func (s *DuplicateScreener) Name() string { return "duplicate_text" }
+
+func (s *DuplicateScreener) Screen(ctx context.Context, in ScreenInput) ([]models.ScreeningFlag, error) {
+	// short comments like "great wifi" are legitimately repeated, and
+	// blank ones all share the empty hash
+	if n := len([]rune(strings.Join(screeningWords(in.Text), " "))); n == 0 || n < s.minLen {
+		return nil, nil
+	}
+	filter := bson.M{
+		"text_hash": ReviewTextHash(in.Text),
+		"hub_id":    bson.M{"$ne": in.HubID},
+	}
+	if !in.ReviewID.IsZero() {
+		filter["_id"] = bson.M{"$ne": in.ReviewID}
+	}
+	n, err := s.cfg.MongoClient.Database(s.cfg.DBName).Collection("reviews").CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		return []models.ScreeningFlag{{Check: s.Name(), Reason: models.ReportReasonSpam, Detail: fmt.Sprintf("same text on %d other review(s)", n)}}, nil
+	}
+	return nil, nil
+}
+
+// =============================
+// Velocity
+// =============================
+
+// VelocityScreener holds reviews from users posting faster than max per window
+type VelocityScreener struct {
+	cfg    *config.Config
+	window time.Duration
+	max    int
+}
+
+func NewVelocityScreener(cfg *config.Config, window time.Duration, max int) *VelocityScreener {
+	return &VelocityScreener{cfg: cfg, window: window, max: max}
+}
+
+func (s *VelocityScreener) Name() string { return "velocity" }
+
+func (s *VelocityScreener) Screen(ctx context.Context, in ScreenInput) ([]models.ScreeningFlag, error) {
+	if s.window <= 0 || s.max <= 0 {
+		return nil, nil
+	}
+	filter := bson.M{
+		"user_id":    in.UserID,
+		"created_at": bson.M{"$gte": time.Now().Add(-s.window)},
+	}
+	if !in.ReviewID.IsZero() {
+		filter["_id"] = bson.M{"$ne": in.ReviewID}
+	}
+	n, err := s.cfg.MongoClient.Database(s.cfg.DBName).Collection("reviews").CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if int(n) >= s.max {
+		return []models.ScreeningFlag{{Check: s.Name(), Reason: models.ReportReasonSpam, Detail: fmt.Sprintf("%d reviews in %s", n+1, s.window)}}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	models "github.com/phillip/contribution-tracker-go/models"
+)
+
+func TestScreeningWords(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"blank", "  ", []string{}},
+		{"case and punctuation", "Great WiFi, (fast)!", []string{"great", "wifi", "fast"}},
+		{"leetspeak", "fr33 c0ffee", []string{"free", "coffee"}},
+		{"numbers kept", "ksh 250 at 7:30", []string{"ksh", "250", "at", "7", "30"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := screeningWords(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("screeningWords(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReviewTextHash(t *testing.T) {
+	base := ReviewTextHash("Great wifi and quiet corners")
+	tests := []struct {
+		name string
+		text string
+		same bool
+	}{
+		{"case and spacing", "  GREAT wifi   and quiet corners ", true},
+		{"punctuation", "Great wifi, and quiet corners!", true},
+		{"different words", "Great wifi and loud corners", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReviewTextHash(tt.text); (got == base) != tt.same {
+				t.Errorf("ReviewTextHash(%q) == base is %v, want %v", tt.text, got == base, tt.same)
+			}
+		})
+	}
+	if got := ReviewTextHash(" ... "); got != "" {
+		t.Errorf("ReviewTextHash(blank) = %q, want empty", got)
+	}
+}
+
+func TestWordListScreener(t *testing.T) {
+	s := NewWordListScreener([]string{"scam", "Rip Off", "  "})
+	tests := []struct {
+		name   string
+		text   string
+		detail string
+	}{
+		{"clean", "Good coffee, fair prices", ""},
+		{"blocked word", "Total SCAM.", "blocked word"},
+		{"leetspeak word", "what a 5c4m", "blocked word"},
+		{"word inside another", "scampi was nice", ""},
+		{"blocked phrase", "a real rip-off for the price", "blocked phrase"},
+		{"phrase split by text", "rip the bag off", ""},
+		{"blank", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, err := s.Screen(context.Background(), ScreenInput{Text: tt.text})
+			if err != nil {
+				t.Fatalf("Screen() error = %v", err)
+			}
+			got := ""
+			if len(flags) > 0 {
+				got = flags[0].Detail
+			}
+			if got != tt.detail {
+				t.Errorf("Screen(%q) = %+v, want detail %q", tt.text, flags, tt.detail)
+			}
+		})
+	}
+}
+
+func TestSpamScreener(t *testing.T) {
+	s := NewSpamScreener(1)
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"clean", "Fast wifi, open till 10pm", nil},
+		{"one link allowed", "menu at www.example.com", nil},
+		{"too many links", "see https://a.example.com and cheap-deals.xyz", []string{"2 link(s)"}},
+		{"phone number", "call 0712 345 678 for deals", []string{"phone number"}},
+		{"international number", "WhatsApp +254 712-345-678", []string{"phone number"}},
+		{"prices are not phones", "coffee 250, lunch 1,200", nil},
+		{"both", "www.a.com www.b.com 0712345678", []string{"2 link(s)", "phone number"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, err := s.Screen(context.Background(), ScreenInput{Text: tt.text})
+			if err != nil {
+				t.Fatalf("Screen() error = %v", err)
+			}
+			var got []string
+			for _, f := range flags {
+				if f.Reason != models.ReportReasonSpam {
+					t.Errorf("Reason = %q, want %q", f.Reason, models.ReportReasonSpam)
+				}
+				got = append(got, f.Detail)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Screen(%q) details = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeScreener flags every review, text or not
+type fakeScreener struct{}
+
+func (fakeScreener) Name() string { return "fake" }
+
+func (fakeScreener) Screen(context.Context, ScreenInput) ([]models.ScreeningFlag, error) {
+	return []models.ScreeningFlag{{Check: "fake"}}, nil
+}
+
+func TestScreenReviewBlankText(t *testing.T) {
+	saved := reviewScreeners
+	defer func() { reviewScreeners = saved }()
+	reviewScreeners = []ReviewScreener{NewWordListScreener([]string{"scam"}), NewSpamScreener(0), fakeScreener{}}
+
+	flags := ScreenReview(context.Background(), ScreenInput{Text: ""})
+	if len(flags) != 1 || flags[0].Check != "fake" {
+		t.Errorf("ScreenReview(blank) = %+v, want only the fake check", flags)
+	}
+}
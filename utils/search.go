@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"math"
+	"sort"
+
+	models "github.com/phillip/contribution-tracker-go/models"
+)
+
+// Weights of the search ranking components, each scaled to 0..1
+const (
+	searchWeightText      = 1.0
+	searchWeightRating    = 0.3
+	searchWeightProximity = 0.5
+)
+
+// BayesianRating shrinks an average rating towards a neutral prior so a
+// single 5-star review doesn't outrank many 4.5s. The result is 1..5.
+func BayesianRating(avg float64, reviews int) float64 {
+	return (avg*float64(reviews) + recPriorRating*recPriorReviews) / (float64(reviews) + recPriorReviews)
+}
+
+// SearchRelevance blends a hub's text score (relative to the best match)
+// with its rating and, when known, its distance from the searcher.
+func SearchRelevance(textScore, topTextScore, avgRating float64, reviews int, distanceMeters *float64) float64 {
+	score := 0.0
+	if topTextScore > 0 {
+		score += searchWeightText * textScore / topTextScore
+	}
+	score += searchWeightRating * clamp01((BayesianRating(avgRating, reviews)-1)/4)
+	if distanceMeters != nil {
+		score += searchWeightProximity * math.Exp(-*distanceMeters/recProximityScaleMeters)
+	}
+	return math.Round(score*1000) / 1000
+}
+
+// SortByRelevance orders hubs by Relevance, highest first, breaking ties
+// by ID so results are stable between requests
+func SortByRelevance(hubs []models.Hub) {
+	sort.SliceStable(hubs, func(i, j int) bool {
+		if hubs[i].Relevance != hubs[j].Relevance {
+			return hubs[i].Relevance > hubs[j].Relevance
+		}
+		return hubs[i].ID.Hex() < hubs[j].ID.Hex()
+	})
+}
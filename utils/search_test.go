@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	models "github.com/phillip/contribution-tracker-go/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBayesianRating(t *testing.T) {
+	tests := []struct {
+		name    string
+		avg     float64
+		reviews int
+		want    float64
+	}{
+		{"no reviews is the prior", 0, 0, 3.5},
+		{"few reviews stay near the prior", 5, 5, 4.25},
+		{"many reviews dominate", 1, 95, 1.125},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BayesianRating(tt.avg, tt.reviews); got != tt.want {
+				t.Errorf("BayesianRating(%v, %d) = %v, want %v", tt.avg, tt.reviews, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchRelevance(t *testing.T) {
+	zero, scale := 0.0, recProximityScaleMeters
+	tests := []struct {
+		name      string
+		textScore float64
+		top       float64
+		avg       float64
+		reviews   int
+		distance  *float64
+		want      float64
+	}{
+		{"no text score", 0, 0, 0, 0, nil, 0.188},
+		{"half the best match", 2, 4, 0, 0, nil, 0.688},
+		{"best match at the searcher", 4, 4, 0, 0, &zero, 1.688},
+		{"best match further away", 4, 4, 0, 0, &scale, 1.371},
+		{"well rated", 4, 4, 5, 95, nil, 1.294},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SearchRelevance(tt.textScore, tt.top, tt.avg, tt.reviews, tt.distance)
+			if got != tt.want {
+				t.Errorf("SearchRelevance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortByRelevance(t *testing.T) {
+	hubs := []models.Hub{
+		{ID: testID(3), Relevance: 1},
+		{ID: testID(2), Relevance: 2},
+		{ID: testID(4), Relevance: 0.5},
+		{ID: testID(1), Relevance: 2},
+	}
+	SortByRelevance(hubs)
+
+	got := make([]primitive.ObjectID, len(hubs))
+	for i, h := range hubs {
+		got[i] = h.ID
+	}
+	want := []primitive.ObjectID{testID(1), testID(2), testID(3), testID(4)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortByRelevance() order = %v, want %v", got, want)
+	}
+}
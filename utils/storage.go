@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"time"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Storage is a blob store for uploaded files. Keys are slash separated
+// paths such as "hubs/6523...e1/full_jpeg.jpg".
+type Storage interface {
+	// Name is the provider recorded on assets this storage creates
+	Name() string
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (StoredObject, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// StoredObject is what a backend reports after a successful Put. Key may
+// differ from the requested one (Cloudinary drops the extension).
+type StoredObject struct {
+	Key  string
+	URL  string
+	Size int64
+}
+
+var storage Storage
+
+// InitStorage builds the backend selected by cfg.Storage.Provider and makes
+// it the one used by UploadFile and DeleteAsset.
+func InitStorage(cfg *config.Config) (Storage, error) {
+	var (
+		s   Storage
+		err error
+	)
+	switch cfg.Storage.Provider {
+	case models.ProviderCloudinary:
+		s, err = NewCloudinaryStorage(cfg.Storage.CloudinaryCloudName, cfg.Storage.CloudinaryAPIKey, cfg.Storage.CloudinaryAPISecret)
+	case models.ProviderLocal:
+		s, err = NewLocalStorage(cfg.Storage.LocalDir, cfg.Storage.PublicBaseURL+cfg.Storage.LocalURLPrefix)
+	case models.ProviderS3:
+		s, err = NewS3Storage(cfg.Storage)
+	default:
+		err = fmt.Errorf("unknown storage provider %q", cfg.Storage.Provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	storage = s
+	log.Printf("🗄️ Storage provider: %s", s.Name())
+	return s, nil
+}
+
+// GetStorage returns the active storage backend
+func GetStorage() Storage {
+	return storage
+}
+
+// UploadFile processes a validated image (orientation, metadata stripping,
+// resized JPEG/WebP variants), stores every variant under folder and records
+// the asset. The original upload is never stored.
+func UploadFile(cfg *config.Config, folder string, upload ImageUpload, uploadedBy primitive.ObjectID) (models.Asset, error) {
+	if storage == nil {
+		return models.Asset{}, fmt.Errorf("storage not initialised")
+	}
+
+	file, err := upload.File.Open()
+	if err != nil {
+		return models.Asset{}, fmt.Errorf("open file: %w", err)
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		return models.Asset{}, fmt.Errorf("read file: %w", err)
+	}
+
+	processed, err := ProcessImage(data, upload.ContentType)
+	if err != nil {
+		return models.Asset{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	asset := models.Asset{
+		ID:         primitive.NewObjectID(),
+		Provider:   storage.Name(),
+		Width:      processed.Width,
+		Height:     processed.Height,
+		BlurHash:   processed.BlurHash,
+		UploadedBy: uploadedBy,
+		CreatedAt:  time.Now(),
+	}
+
+	for _, v := range processed.Variants {
+		key := path.Join(folder, asset.ID.Hex(), v.Name+"_"+v.Format+v.Ext)
+		obj, err := storage.Put(ctx, key, bytes.NewReader(v.Data), int64(len(v.Data)), v.ContentType)
+		if err != nil {
+			deleteKeys(ctx, asset.Keys())
+			return models.Asset{}, fmt.Errorf("upload error: %w", err)
+		}
+
+		asset.Variants = append(asset.Variants, models.AssetVariant{
+			Name:        v.Name,
+			Format:      v.Format,
+			Key:         obj.Key,
+			URL:         obj.URL,
+			ContentType: v.ContentType,
+			Width:       v.Width,
+			Height:      v.Height,
+			Size:        int64(len(v.Data)),
+		})
+		if v.Name == "full" && v.Format == "jpeg" {
+			asset.Key = obj.Key
+			asset.URL = obj.URL
+			asset.ContentType = v.ContentType
+			asset.Size = int64(len(v.Data))
+		}
+	}
+
+	if _, err := cfg.MongoClient.Database(cfg.DBName).Collection("assets").InsertOne(ctx, asset); err != nil {
+		// don't leave untracked objects behind
+		deleteKeys(ctx, asset.Keys())
+		return models.Asset{}, fmt.Errorf("record asset: %w", err)
+	}
+	return asset, nil
+}
+
+// DeleteAsset removes every stored object of the asset and its record
+func DeleteAsset(cfg *config.Config, asset models.Asset) error {
+	if storage == nil {
+		return fmt.Errorf("storage not initialised")
+	}
+	if asset.Provider != storage.Name() {
+		return fmt.Errorf("asset %s is stored in %q but active storage is %q", asset.URL, asset.Provider, storage.Name())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, key := range asset.Keys() {
+		if err := storage.Delete(ctx, key); err != nil {
+			return fmt.Errorf("delete error: %w", err)
+		}
+	}
+	if !asset.ID.IsZero() {
+		_, err := cfg.MongoClient.Database(cfg.DBName).Collection("assets").DeleteOne(ctx, bson.M{"_id": asset.ID})
+		return err
+	}
+	return nil
+}
+
+// deleteKeys best-effort removes objects from a failed upload
+func deleteKeys(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := storage.Delete(ctx, key); err != nil {
+			log.Printf("⚠️ could not delete %s: %v", key, err)
+		}
+	}
+}
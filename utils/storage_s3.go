@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+)
+
+// S3Storage stores files in an S3-compatible bucket (AWS S3, MinIO)
+type S3Storage struct {
+	client  *minio.Client
+	bucket  string
+	baseURL string
+}
+
+func NewS3Storage(sc config.StorageConfig) (*S3Storage, error) {
+	endpoint := strings.TrimPrefix(strings.TrimPrefix(sc.S3Endpoint, "https://"), "http://")
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(sc.S3AccessKey, sc.S3SecretKey, ""),
+		Secure: sc.S3UseSSL,
+		Region: sc.S3Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3 config error: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	exists, err := client.BucketExists(ctx, sc.S3Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("s3 bucket check: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("s3 bucket %q does not exist", sc.S3Bucket)
+	}
+
+	baseURL := sc.S3PublicURL
+	if baseURL == "" {
+		scheme := "https"
+		if !sc.S3UseSSL {
+			scheme = "http"
+		}
+		baseURL = fmt.Sprintf("%s://%s/%s", scheme, endpoint, sc.S3Bucket)
+	}
+
+	return &S3Storage{client: client, bucket: sc.S3Bucket, baseURL: baseURL}, nil
+}
+
+func (s *S3Storage) Name() string { return models.ProviderS3 }
+
+func (s *S3Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) (StoredObject, error) {
+	if size <= 0 {
+		size = -1
+	}
+	info, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return StoredObject{}, err
+	}
+	return StoredObject{
+		Key:  key,
+		URL:  s.baseURL + "/" + key,
+		Size: info.Size,
+	}, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
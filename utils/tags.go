@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// MaxHubTags caps the free-form tags on one hub
+	MaxHubTags = 10
+	// maxTagLen is the longest tag kept, in runes
+	maxTagLen = 32
+)
+
+// NormalizeTag folds a free-form tag or term name into a slug: lowercase,
+// accents dropped, runs of anything else turned into single hyphens.
+// "Café  Lounge!" becomes "cafe-lounge".
+func NormalizeTag(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range norm.NFD.String(strings.ToLower(strings.TrimSpace(s))) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// combining accent
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		default:
+			hyphen = true
+		}
+	}
+	tag := []rune(b.String())
+	if len(tag) > maxTagLen {
+		tag = tag[:maxTagLen]
+	}
+	return strings.TrimRight(string(tag), "-")
+}
+
+// ResolveTags normalizes tags, maps synonyms onto their canonical tag and
+// drops blanks and repeats, keeping the caller's order
+func ResolveTags(ctx context.Context, cfg *config.Config, raw []string) ([]string, error) {
+	normalized := []string{}
+	for _, t := range raw {
+		for _, part := range strings.Split(t, ",") {
+			if tag := NormalizeTag(part); tag != "" {
+				normalized = append(normalized, tag)
+			}
+		}
+	}
+	if len(normalized) == 0 {
+		return []string{}, nil
+	}
+
+	cursor, err := cfg.MongoClient.Database(cfg.DBName).Collection("tag_synonyms").
+		Find(ctx, bson.M{"alias": bson.M{"$in": normalized}})
+	if err != nil {
+		return nil, err
+	}
+	var synonyms []models.TagSynonym
+	if err := cursor.All(ctx, &synonyms); err != nil {
+		return nil, err
+	}
+	canonical := map[string]string{}
+	for _, s := range synonyms {
+		canonical[s.Alias] = s.Tag
+	}
+
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, t := range normalized {
+		if c, ok := canonical[t]; ok {
+			t = c
+		}
+		if !seen[t] {
+			seen[t] = true
+			tags = append(tags, t)
+		}
+	}
+	return tags, nil
+}
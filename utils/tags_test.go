@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"already a slug", "quiet", "quiet"},
+		{"case", "Quiet", "quiet"},
+		{"accents", "Café", "cafe"},
+		{"punctuation to one hyphen", "Café  Lounge!", "cafe-lounge"},
+		{"leading and trailing junk", "  --power outlets--  ", "power-outlets"},
+		{"digits kept", "24/7", "24-7"},
+		{"nothing left", "!!!", ""},
+		{"truncated", strings.Repeat("a", 40), strings.Repeat("a", maxTagLen)},
+		{"no hyphen left at the cut", strings.Repeat("a", maxTagLen-1) + " b", strings.Repeat("a", maxTagLen-1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeTag(tt.in); got != tt.want {
+				t.Errorf("NormalizeTag(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
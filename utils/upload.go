@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strings"
+)
+
+// Upload error codes returned to clients
+const (
+	UploadTooLarge        = "file_too_large"
+	UploadRequestTooLarge = "request_too_large"
+	UploadTooMany         = "too_many_files"
+	UploadUnsupported     = "unsupported_type"
+	UploadUnreadable      = "unreadable"
+)
+
+// UploadError describes why a single file (or the request) was rejected
+type UploadError struct {
+	Field  string `json:"field,omitempty"`
+	File   string `json:"file,omitempty"`
+	Index  int    `json:"index"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// UploadErrors collects every rejected file so clients can fix them all at once
+type UploadErrors []UploadError
+
+func (e UploadErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, ue := range e {
+		if ue.File != "" {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", ue.File, ue.Reason))
+		} else {
+			msgs = append(msgs, ue.Reason)
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ImageUpload is a multipart file that passed validation. ContentType and
+// Ext come from sniffing the bytes, not from what the client claimed.
+type ImageUpload struct {
+	File        *multipart.FileHeader
+	ContentType string
+	Ext         string
+}
+
+// ImageLimits bounds a batch of images for one field
+type ImageLimits struct {
+	MaxFileBytes int64
+	MaxFiles     int // total images allowed, including ones already stored
+	Existing     int // images already attached to the target
+}
+
+// ValidateImages checks count, size and real content type of every file
+// under field. All problems are reported together.
+func ValidateImages(field string, files []*multipart.FileHeader, limits ImageLimits) ([]ImageUpload, error) {
+	var errs UploadErrors
+
+	if limits.MaxFiles > 0 && limits.Existing+len(files) > limits.MaxFiles {
+		errs = append(errs, UploadError{
+			Field:  field,
+			Index:  -1,
+			Code:   UploadTooMany,
+			Reason: fmt.Sprintf("at most %d images allowed, got %d", limits.MaxFiles, limits.Existing+len(files)),
+		})
+	}
+
+	uploads := make([]ImageUpload, 0, len(files))
+	for i, fh := range files {
+		fail := func(code, reason string) {
+			errs = append(errs, UploadError{Field: field, File: fh.Filename, Index: i, Code: code, Reason: reason})
+		}
+
+		if limits.MaxFileBytes > 0 && fh.Size > limits.MaxFileBytes {
+			fail(UploadTooLarge, fmt.Sprintf("file is %s, limit is %s", humanBytes(fh.Size), humanBytes(limits.MaxFileBytes)))
+			continue
+		}
+
+		head, err := readHead(fh, 32)
+		if err != nil {
+			fail(UploadUnreadable, "could not read file")
+			continue
+		}
+
+		contentType, ext, ok := SniffImageType(head)
+		if !ok {
+			fail(UploadUnsupported, "only JPEG, PNG, WebP and HEIC images are allowed")
+			continue
+		}
+
+		uploads = append(uploads, ImageUpload{File: fh, ContentType: contentType, Ext: ext})
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return uploads, nil
+}
+
+// heifBrands are ISO-BMFF major brands used by HEIC/HEIF stills
+var heifBrands = map[string]bool{
+	"heic": true, "heix": true, "heim": true, "heis": true,
+	"hevc": true, "hevx": true, "mif1": true, "msf1": true,
+}
+
+// SniffImageType identifies supported images by their magic bytes
+func SniffImageType(head []byte) (contentType, ext string, ok bool) {
+	switch {
+	case len(head) >= 3 && bytes.Equal(head[:3], []byte{0xFF, 0xD8, 0xFF}):
+		return "image/jpeg", ".jpg", true
+	case len(head) >= 8 && bytes.Equal(head[:8], []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}):
+		return "image/png", ".png", true
+	case len(head) >= 12 && string(head[:4]) == "RIFF" && string(head[8:12]) == "WEBP":
+		return "image/webp", ".webp", true
+	case len(head) >= 12 && string(head[4:8]) == "ftyp" && heifBrands[string(head[8:12])]:
+		return "image/heic", ".heic", true
+	}
+	return "", "", false
+}
+
+func readHead(fh *multipart.FileHeader, n int) ([]byte, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, n)
+	read, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	return buf[:read], nil
+}
+
+func humanBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UploadImages stores uploads in parallel with at most cfg.Uploads.Concurrency
+// in flight. It is all-or-nothing: if any file fails, every file that did
+// make it is deleted again. Results are always returned in input order.
+func UploadImages(cfg *config.Config, folder string, uploads []ImageUpload, uploadedBy primitive.ObjectID) ([]models.Asset, []models.UploadResult, error) {
+	results := make([]models.UploadResult, len(uploads))
+	assets := make([]models.Asset, len(uploads))
+	if len(uploads) == 0 {
+		return []models.Asset{}, results, nil
+	}
+
+	workers := cfg.Uploads.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(uploads) {
+		workers = len(uploads)
+	}
+
+	jobs := make(chan int)
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				up := uploads[i]
+				results[i] = models.UploadResult{File: up.File.Filename, Index: i}
+
+				// Once something failed the batch is rolled back anyway
+				mu.Lock()
+				skip := failed
+				mu.Unlock()
+				if skip {
+					results[i].Status = models.UploadStatusSkipped
+					continue
+				}
+
+				asset, err := UploadFile(cfg, folder, up, uploadedBy)
+				if err != nil {
+					mu.Lock()
+					failed = true
+					mu.Unlock()
+					results[i].Status = models.UploadStatusFailed
+					results[i].Error = err.Error()
+					continue
+				}
+				assets[i] = asset
+				results[i].Status = models.UploadStatusUploaded
+				results[i].AssetID = asset.ID.Hex()
+				results[i].URL = asset.URL
+			}
+		}()
+	}
+	for i := range uploads {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if !failed {
+		return assets, results, nil
+	}
+
+	// Compensate: remove everything that was stored
+	var stored []models.Asset
+	for i, r := range results {
+		if r.Status == models.UploadStatusUploaded {
+			stored = append(stored, assets[i])
+			results[i].Status = models.UploadStatusRolledBack
+		}
+	}
+	RollbackUploads(cfg, stored)
+
+	return nil, results, fmt.Errorf("image upload failed")
+}
+
+// RollbackUploads deletes assets stored for a request that did not complete.
+// Failures are logged; the asset reconciler removes leftovers later.
+func RollbackUploads(cfg *config.Config, assets []models.Asset) {
+	for _, a := range assets {
+		if err := DeleteAsset(cfg, a); err != nil {
+			log.Printf("⚠️ rollback: could not delete asset %s (%s): %v", a.ID.Hex(), a.URL, err)
+		}
+	}
+}
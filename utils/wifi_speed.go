@@ -0,0 +1,283 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	config "github.com/phillip/contribution-tracker-go/config"
+	models "github.com/phillip/contribution-tracker-go/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// WiFiStatsSamples is how many recent tests the medians are taken over
+	WiFiStatsSamples = 30
+	// WiFiStatsMaxAge drops tests older than this from the medians
+	WiFiStatsMaxAge = 30 * 24 * time.Hour
+
+	// plausible ranges for a single test
+	maxWiFiMbps      = 2000.0
+	maxWiFiLatencyMs = 5000.0
+
+	// a value further than this many scaled MADs from the median of recent
+	// tests is treated as an outlier, once there are enough tests to judge
+	wifiOutlierMADs       = 5.0
+	wifiOutlierMinSamples = 5
+
+	// rejected tests from this many users that agree within
+	// wifiShiftTolerance of each other, inside wifiShiftWindow, mean the
+	// connection really changed (e.g. an upgrade) rather than a bad test
+	wifiShiftUsers     = 3
+	wifiShiftTolerance = 0.3
+	wifiShiftWindow    = 7 * 24 * time.Hour
+)
+
+// ValidateWiFiTest checks a submission for impossible values and clock
+// skew. It returns a reason for rejection, or "" if it is plausible.
+func ValidateWiFiTest(t models.WiFiSpeedTest, now time.Time) string {
+	switch {
+	case t.DownloadMbps <= 0 || t.DownloadMbps > maxWiFiMbps:
+		return fmt.Sprintf("download must be between 0 and %.0f Mbps", maxWiFiMbps)
+	case t.UploadMbps <= 0 || t.UploadMbps > maxWiFiMbps:
+		return fmt.Sprintf("upload must be between 0 and %.0f Mbps", maxWiFiMbps)
+	case t.LatencyMs <= 0 || t.LatencyMs > maxWiFiLatencyMs:
+		return fmt.Sprintf("latency must be between 0 and %.0f ms", maxWiFiLatencyMs)
+	case t.MeasuredAt.After(now.Add(5 * time.Minute)):
+		return "measured_at is in the future"
+	case t.MeasuredAt.Before(now.Add(-24 * time.Hour)):
+		return "test is more than a day old"
+	}
+	return ""
+}
+
+// WiFiOutlier compares a test with recent ones for the same hub and
+// returns which metric is an outlier, or "" if none is.
+func WiFiOutlier(t models.WiFiSpeedTest, recent []models.WiFiSpeedTest) string {
+	if len(recent) < wifiOutlierMinSamples {
+		return ""
+	}
+	metrics := []struct {
+		name  string
+		value float64
+		pick  func(models.WiFiSpeedTest) float64
+	}{
+		{"download", t.DownloadMbps, func(r models.WiFiSpeedTest) float64 { return r.DownloadMbps }},
+		{"upload", t.UploadMbps, func(r models.WiFiSpeedTest) float64 { return r.UploadMbps }},
+		{"latency", t.LatencyMs, func(r models.WiFiSpeedTest) float64 { return r.LatencyMs }},
+	}
+	for _, m := range metrics {
+		values := make([]float64, len(recent))
+		for i, r := range recent {
+			values[i] = m.pick(r)
+		}
+		med := Median(values)
+		mad := MedianAbsDeviation(values, med) * 1.4826
+		// floor the spread so a run of identical results doesn't reject
+		// everything that differs slightly
+		mad = math.Max(mad, med*0.1)
+		if mad > 0 && math.Abs(m.value-med) > wifiOutlierMADs*mad {
+			return m.name
+		}
+	}
+	return ""
+}
+
+// WiFiShift reports whether the outlier t is backed by earlier rejected
+// tests from enough different users, together with the rejected tests that
+// agree with it
+func WiFiShift(t models.WiFiSpeedTest, rejected []models.WiFiSpeedTest) ([]models.WiFiSpeedTest, bool) {
+	near := func(a, b float64) bool {
+		return math.Abs(a-b) <= wifiShiftTolerance*math.Max(a, b)
+	}
+	agreeing := []models.WiFiSpeedTest{}
+	users := map[primitive.ObjectID]bool{t.UserID: true}
+	for _, r := range rejected {
+		if near(r.DownloadMbps, t.DownloadMbps) && near(r.UploadMbps, t.UploadMbps) && near(r.LatencyMs, t.LatencyMs) {
+			agreeing = append(agreeing, r)
+			users[r.UserID] = true
+		}
+	}
+	return agreeing, len(users) >= wifiShiftUsers
+}
+
+// Median returns the median of values (0 if empty) without modifying them
+func Median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	s := append([]float64(nil), values...)
+	sort.Float64s(s)
+	mid := len(s) / 2
+	if len(s)%2 == 0 {
+		return (s[mid-1] + s[mid]) / 2
+	}
+	return s[mid]
+}
+
+// MedianAbsDeviation returns the median of |v - med|
+func MedianAbsDeviation(values []float64, med float64) float64 {
+	dev := make([]float64, len(values))
+	for i, v := range values {
+		dev[i] = math.Abs(v - med)
+	}
+	return Median(dev)
+}
+
+// RecentWiFiTests returns a hub's latest accepted tests within
+// WiFiStatsMaxAge
+func RecentWiFiTests(ctx context.Context, cfg *config.Config, hubID primitive.ObjectID) ([]models.WiFiSpeedTest, error) {
+	return findWiFiTests(ctx, cfg, bson.M{
+		"hub_id":      hubID,
+		"measured_at": bson.M{"$gte": time.Now().Add(-WiFiStatsMaxAge)},
+		"rejected":    bson.M{"$ne": true},
+		"superseded":  bson.M{"$ne": true},
+	}, WiFiStatsSamples)
+}
+
+// RecentRejectedWiFiTests returns a hub's outliers within wifiShiftWindow
+func RecentRejectedWiFiTests(ctx context.Context, cfg *config.Config, hubID primitive.ObjectID) ([]models.WiFiSpeedTest, error) {
+	return findWiFiTests(ctx, cfg, bson.M{
+		"hub_id":      hubID,
+		"measured_at": bson.M{"$gte": time.Now().Add(-wifiShiftWindow)},
+		"rejected":    true,
+	}, 2*WiFiStatsSamples)
+}
+
+// AcceptWiFiShift counts the agreeing outliers and drops every older
+// accepted test from the medians, so they describe the connection as it
+// is now
+func AcceptWiFiShift(ctx context.Context, cfg *config.Config, t models.WiFiSpeedTest, agreeing []models.WiFiSpeedTest) error {
+	col := cfg.MongoClient.Database(cfg.DBName).Collection("wifi_tests")
+
+	since := t.MeasuredAt
+	ids := make([]primitive.ObjectID, 0, len(agreeing))
+	for _, r := range agreeing {
+		ids = append(ids, r.ID)
+		if r.MeasuredAt.Before(since) {
+			since = r.MeasuredAt
+		}
+	}
+	if _, err := col.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": ids}},
+		bson.M{"$unset": bson.M{"rejected": "", "rejected_metric": ""}},
+	); err != nil {
+		return err
+	}
+	_, err := col.UpdateMany(ctx, bson.M{
+		"hub_id":      t.HubID,
+		"measured_at": bson.M{"$lt": since},
+		"rejected":    bson.M{"$ne": true},
+	}, bson.M{"$set": bson.M{"superseded": true}})
+	return err
+}
+
+func findWiFiTests(ctx context.Context, cfg *config.Config, filter bson.M, limit int64) ([]models.WiFiSpeedTest, error) {
+	cursor, err := cfg.MongoClient.Database(cfg.DBName).Collection("wifi_tests").Find(ctx, filter,
+		options.Find().SetSort(bson.M{"measured_at": -1}).SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	tests := []models.WiFiSpeedTest{}
+	if err := cursor.All(ctx, &tests); err != nil {
+		return nil, err
+	}
+	return tests, nil
+}
+
+// WiFiStatsFor summarises tests as medians; nil when there are none
+func WiFiStatsFor(tests []models.WiFiSpeedTest, now time.Time) *models.WiFiStats {
+	if len(tests) == 0 {
+		return nil
+	}
+	down := make([]float64, len(tests))
+	up := make([]float64, len(tests))
+	lat := make([]float64, len(tests))
+	seen := map[string]bool{}
+	stats := &models.WiFiStats{Samples: len(tests), UpdatedAt: now}
+	for i, t := range tests {
+		down[i], up[i], lat[i] = t.DownloadMbps, t.UploadMbps, t.LatencyMs
+		if t.SSID != "" && !seen[t.SSID] {
+			seen[t.SSID] = true
+			stats.SSIDs = append(stats.SSIDs, t.SSID)
+		}
+	}
+	stats.DownloadMbps = math.Round(Median(down)*10) / 10
+	stats.UploadMbps = math.Round(Median(up)*10) / 10
+	stats.LatencyMs = math.Round(Median(lat))
+	return stats
+}
+
+// RefreshHubWiFi recomputes the medians stored on the hub
+func RefreshHubWiFi(ctx context.Context, cfg *config.Config, hubID primitive.ObjectID) (*models.WiFiStats, error) {
+	tests, err := RecentWiFiTests(ctx, cfg, hubID)
+	if err != nil {
+		return nil, err
+	}
+	stats := WiFiStatsFor(tests, time.Now())
+
+	update := bson.M{"$set": bson.M{"wifi": stats}}
+	if stats == nil {
+		update = bson.M{"$unset": bson.M{"wifi": ""}}
+	}
+	_, err = cfg.MongoClient.Database(cfg.DBName).Collection("hubs").UpdateOne(ctx, bson.M{"_id": hubID}, update)
+	return stats, err
+}
+
+// RefreshAllHubWiFi recomputes the stored medians of every hub that has
+// them, so tests ageing out of the window are reflected without a new
+// submission
+func RefreshAllHubWiFi(ctx context.Context, cfg *config.Config) (int, error) {
+	ids, err := cfg.MongoClient.Database(cfg.DBName).Collection("hubs").
+		Distinct(ctx, "_id", bson.M{"wifi": bson.M{"$exists": true}})
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, id := range ids {
+		hubID, ok := id.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		if _, err := RefreshHubWiFi(ctx, cfg, hubID); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// StartWiFiRefreshJob recomputes hub wifi medians at startup and then every
+// cfg.WiFi.RefreshInterval, so tests ageing out are dropped
+func StartWiFiRefreshJob(cfg *config.Config) {
+	if cfg.WiFi.RefreshInterval <= 0 {
+		log.Println("📶 WiFi refresh job disabled")
+		return
+	}
+
+	run := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		if n, err := RefreshAllHubWiFi(ctx, cfg); err != nil {
+			log.Printf("⚠️ WiFi stats refresh failed: %v", err)
+		} else {
+			log.Printf("📶 WiFi stats refreshed for %d hub(s)", n)
+		}
+	}
+
+	go func() {
+		run()
+		ticker := time.NewTicker(cfg.WiFi.RefreshInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			run()
+		}
+	}()
+	log.Printf("📶 WiFi stats refreshed every %s", cfg.WiFi.RefreshInterval)
+}
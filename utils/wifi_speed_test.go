@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	models "github.com/phillip/contribution-tracker-go/models"
+)
+
+func speedTest(user byte, down, up, latency float64) models.WiFiSpeedTest {
+	return models.WiFiSpeedTest{UserID: testID(user), DownloadMbps: down, UploadMbps: up, LatencyMs: latency}
+}
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"odd", []float64{3, 1, 2}, 2},
+		{"even", []float64{4, 1, 3, 2}, 2.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Median(tt.values); got != tt.want {
+				t.Errorf("Median(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+
+	values := []float64{3, 1, 2}
+	Median(values)
+	if values[0] != 3 || values[1] != 1 || values[2] != 2 {
+		t.Errorf("Median() reordered its input: %v", values)
+	}
+	if got := MedianAbsDeviation([]float64{1, 2, 3, 4, 100}, 3); got != 1 {
+		t.Errorf("MedianAbsDeviation() = %v, want 1", got)
+	}
+}
+
+func TestValidateWiFiTest(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	valid := speedTest(1, 40, 10, 25)
+	valid.MeasuredAt = now.Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		change func(*models.WiFiSpeedTest)
+		ok     bool
+	}{
+		{"plausible", func(*models.WiFiSpeedTest) {}, true},
+		{"zero download", func(t *models.WiFiSpeedTest) { t.DownloadMbps = 0 }, false},
+		{"impossible upload", func(t *models.WiFiSpeedTest) { t.UploadMbps = 5000 }, false},
+		{"impossible latency", func(t *models.WiFiSpeedTest) { t.LatencyMs = 9000 }, false},
+		{"small clock skew", func(t *models.WiFiSpeedTest) { t.MeasuredAt = now.Add(2 * time.Minute) }, true},
+		{"in the future", func(t *models.WiFiSpeedTest) { t.MeasuredAt = now.Add(time.Hour) }, false},
+		{"too old", func(t *models.WiFiSpeedTest) { t.MeasuredAt = now.Add(-48 * time.Hour) }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := valid
+			tt.change(&test)
+			if reason := ValidateWiFiTest(test, now); (reason == "") != tt.ok {
+				t.Errorf("ValidateWiFiTest() = %q, want ok %v", reason, tt.ok)
+			}
+		})
+	}
+}
+
+func TestWiFiOutlier(t *testing.T) {
+	recent := []models.WiFiSpeedTest{
+		speedTest(1, 10, 5, 20), speedTest(2, 11, 5, 22), speedTest(3, 9, 5, 19),
+		speedTest(4, 10, 5, 21), speedTest(5, 12, 5, 20), speedTest(6, 10, 5, 20),
+	}
+	tests := []struct {
+		name   string
+		test   models.WiFiSpeedTest
+		recent []models.WiFiSpeedTest
+		want   string
+	}{
+		{"too few tests to judge", speedTest(9, 100, 5, 20), recent[:4], ""},
+		{"in line", speedTest(9, 10.5, 5.2, 21), recent, ""},
+		{"small spread is floored", speedTest(9, 13, 5.4, 23), recent, ""},
+		{"download far above", speedTest(9, 100, 5, 20), recent, "download"},
+		{"upload far above", speedTest(9, 10, 50, 20), recent, "upload"},
+		{"latency far above", speedTest(9, 10, 5, 300), recent, "latency"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WiFiOutlier(tt.test, tt.recent); got != tt.want {
+				t.Errorf("WiFiOutlier() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWiFiShift(t *testing.T) {
+	upgraded := speedTest(1, 100, 40, 15)
+	tests := []struct {
+		name     string
+		rejected []models.WiFiSpeedTest
+		agreeing int
+		shift    bool
+	}{
+		{"nobody else", nil, 0, false},
+		{"one other user", []models.WiFiSpeedTest{speedTest(2, 95, 38, 16)}, 1, false},
+		{
+			"two other users agree",
+			[]models.WiFiSpeedTest{speedTest(2, 95, 38, 16), speedTest(3, 110, 42, 14)},
+			2, true,
+		},
+		{
+			"the same user twice",
+			[]models.WiFiSpeedTest{speedTest(2, 95, 38, 16), speedTest(2, 105, 41, 15)},
+			2, false,
+		},
+		{
+			"the submitter's own earlier test",
+			[]models.WiFiSpeedTest{speedTest(1, 98, 40, 15), speedTest(2, 95, 38, 16)},
+			2, false,
+		},
+		{
+			"others measured something else",
+			[]models.WiFiSpeedTest{speedTest(2, 1, 0.5, 900), speedTest(3, 300, 40, 15)},
+			0, false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agreeing, shift := WiFiShift(upgraded, tt.rejected)
+			if len(agreeing) != tt.agreeing || shift != tt.shift {
+				t.Errorf("WiFiShift() = %d agreeing, %v; want %d, %v", len(agreeing), shift, tt.agreeing, tt.shift)
+			}
+		})
+	}
+}
+
+func TestWiFiStatsFor(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	if got := WiFiStatsFor(nil, now); got != nil {
+		t.Errorf("WiFiStatsFor(nil) = %+v, want nil", got)
+	}
+
+	tests := []models.WiFiSpeedTest{
+		speedTest(1, 10.04, 5, 20), speedTest(2, 30, 7, 30), speedTest(3, 20, 6, 25.4),
+	}
+	tests[0].SSID, tests[1].SSID, tests[2].SSID = "Hub", "Hub 5G", "Hub"
+	got := WiFiStatsFor(tests, now)
+	want := models.WiFiStats{DownloadMbps: 20, UploadMbps: 6, LatencyMs: 25, Samples: 3, SSIDs: []string{"Hub", "Hub 5G"}, UpdatedAt: now}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("WiFiStatsFor() = %+v, want %+v", *got, want)
+	}
+}